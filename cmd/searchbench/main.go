@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/Spoloborota/experiment/internal/config"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/repository"
+)
+
+// seedBatch - сколько анкет создается одним запросом
+const seedBatch = 50000
+
+// Синтетические пользователи отличаются по email, чтобы их можно было удалить, не задев настоящих
+const seedEmailPattern = "searchbench-%@example.invalid"
+
+// Индексы, ускорение от которых измеряет бенчмарк, и их определения из миграции 019_profile_name_search.sql
+var (
+	nameIndexes      = []string{"idx_profiles_first_name_prefix", "idx_profiles_last_name_prefix"}
+	nameIndexColumns = []string{"lower(first_name) text_pattern_ops", "lower(last_name) text_pattern_ops"}
+)
+
+// Из этих частей составляются имена и фамилии синтетических анкет и префиксы для поиска
+var (
+	firstNames = []string{
+		"Alexander", "Alexey", "Anna", "Andrey", "Boris", "Daria", "Dmitry", "Elena", "Egor", "Ekaterina",
+		"Fedor", "Galina", "Grigory", "Igor", "Irina", "Ivan", "Kirill", "Ksenia", "Lev", "Maria",
+		"Maxim", "Mikhail", "Natalia", "Nikita", "Oleg", "Olga", "Pavel", "Polina", "Roman", "Sergey",
+		"Sofia", "Stepan", "Svetlana", "Tatiana", "Timofey", "Ulyana", "Vera", "Viktor", "Yulia", "Zakhar",
+		"Алексей", "Анна", "Дмитрий", "Елена", "Иван", "Мария", "Никита", "Ольга", "Сергей", "Татьяна",
+	}
+	lastNameStems = []string{
+		"ab", "bel", "bog", "vas", "vol", "gor", "gro", "dav", "zai", "iva", "kar", "kov", "koz", "kuz", "leb",
+		"mak", "mar", "mor", "nik", "nov", "orl", "pav", "pet", "pop", "rom", "sem", "sid", "smi", "sok", "ste",
+		"tar", "tit", "fed", "fil", "fro", "kha", "che", "sha", "shch", "yak",
+	}
+	lastNameEndings = []string{
+		"anov", "arev", "enko", "ilin", "inov", "kin", "kov", "lev", "makov", "nikov",
+		"ochkin", "onov", "orov", "ovsky", "ukhin", "urin", "yshev", "yev", "zhenko", "tsev",
+	}
+	cities = []string{"Москва", "Санкт-Петербург", "Новосибирск", "Екатеринбург", "Казань", "Нижний Новгород", "Самара", "Омск"}
+)
+
+var (
+	flags       = flag.NewFlagSet("searchbench", flag.ExitOnError)
+	profiles    = flags.Int("profiles", 1000000, "number of synthetic profiles for seed")
+	duration    = flags.Duration("duration", 30*time.Second, "how long each scenario runs")
+	concurrency = flags.Int("concurrency", 8, "number of parallel searches")
+	limit       = flags.Int("limit", 10, "page size of each search")
+)
+
+// scenario - вид поискового запроса, задержка которого измеряется
+type scenario struct {
+	name    string
+	filters func(rnd *rand.Rand) repositories.SearchFilters
+}
+
+// result содержит замеры одного сценария
+type result struct {
+	name      string
+	latencies []time.Duration
+	errors    int
+	elapsed   time.Duration
+}
+
+func main() {
+	flags.Usage = usage
+	flags.Parse(os.Args[1:])
+
+	args := flags.Args()
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		flags.Usage()
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL())
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	db.SetMaxOpenConns(*concurrency + 1)
+	db.SetMaxIdleConns(*concurrency + 1)
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "seed":
+		if err := seed(ctx, db, *profiles); err != nil {
+			log.Fatalf("Failed to seed profiles: %v", err)
+		}
+	case "run":
+		if err := run(ctx, db); err != nil {
+			log.Fatalf("Failed to run benchmark: %v", err)
+		}
+	case "drop-indexes":
+		if err := dropIndexes(ctx, db); err != nil {
+			log.Fatalf("Failed to drop indexes: %v", err)
+		}
+	case "create-indexes":
+		if err := createIndexes(ctx, db); err != nil {
+			log.Fatalf("Failed to create indexes: %v", err)
+		}
+	case "cleanup":
+		if err := cleanup(ctx, db); err != nil {
+			log.Fatalf("Failed to clean up profiles: %v", err)
+		}
+	default:
+		fmt.Printf("Unknown command: %s\n", args[0])
+		flags.Usage()
+	}
+}
+
+// seed создает синтетических пользователей с анкетами, пока их не станет count.
+// Повторный запуск досоздает недостающие анкеты
+func seed(ctx context.Context, db *sql.DB, count int) error {
+	for from := 1; from <= count; from += seedBatch {
+		to := min(from+seedBatch-1, count)
+
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO users (email, password_hash)
+			SELECT 'searchbench-' || n || '@example.invalid', '!'
+			FROM generate_series($1::int, $2::int) AS n
+			ON CONFLICT (email) DO NOTHING`,
+			from, to,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert users: %w", err)
+		}
+
+		// Части имени выбираются по хешу номера, поэтому данные одинаковы при каждом запуске
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO profiles (user_id, first_name, last_name, birth_date, gender, city)
+			SELECT users.id,
+				($3::text[])[1 + (hashint4(n) & 2147483647) % cardinality($3::text[])],
+				initcap(($4::text[])[1 + (hashint4(n + 1) & 2147483647) % cardinality($4::text[])] ||
+					($5::text[])[1 + (hashint4(n + 2) & 2147483647) % cardinality($5::text[])]),
+				DATE '1950-01-01' + (hashint4(n + 3) & 2147483647) % 20000,
+				(ARRAY['male', 'female'])[1 + n % 2],
+				($6::text[])[1 + (hashint4(n + 4) & 2147483647) % cardinality($6::text[])]
+			FROM generate_series($1::int, $2::int) AS n
+			JOIN users ON users.email = 'searchbench-' || n || '@example.invalid'
+			ON CONFLICT (user_id) DO NOTHING`,
+			from, to, pq.Array(firstNames), pq.Array(lastNameStems), pq.Array(lastNameEndings), pq.Array(cities),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert profiles: %w", err)
+		}
+
+		fmt.Printf("Seeded %d/%d profiles\n", to, count)
+	}
+
+	// Обновляем статистику, чтобы планировщик учитывал новые данные
+	if _, err := db.ExecContext(ctx, "ANALYZE users, profiles"); err != nil {
+		return fmt.Errorf("failed to analyze tables: %w", err)
+	}
+
+	return nil
+}
+
+// cleanup удаляет синтетических пользователей вместе с их анкетами
+func cleanup(ctx context.Context, db *sql.DB) error {
+	res, err := db.ExecContext(ctx, "DELETE FROM users WHERE email LIKE $1", seedEmailPattern)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted %d synthetic users\n", deleted)
+	return nil
+}
+
+// dropIndexes удаляет индексы поиска по имени, чтобы измерить поиск без них
+func dropIndexes(ctx context.Context, db *sql.DB) error {
+	for _, name := range nameIndexes {
+		if _, err := db.ExecContext(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+name); err != nil {
+			return err
+		}
+		fmt.Printf("Dropped index %s\n", name)
+	}
+	return nil
+}
+
+// createIndexes восстанавливает индексы поиска по имени так же, как миграция
+func createIndexes(ctx context.Context, db *sql.DB) error {
+	for i, name := range nameIndexes {
+		query := fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON profiles (%s)", name, nameIndexColumns[i])
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+		fmt.Printf("Created index %s\n", name)
+	}
+	return nil
+}
+
+// run измеряет задержку и пропускную способность поиска по имени так, как его выполняет
+// GET /api/v1/profiles: страница анкет и их общее количество
+func run(ctx context.Context, db *sql.DB) error {
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles").Scan(&total); err != nil {
+		return fmt.Errorf("failed to count profiles: %w", err)
+	}
+
+	present, err := presentIndexes(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Profiles: %d, concurrency: %d, duration per scenario: %s\n", total, *concurrency, *duration)
+	for _, name := range nameIndexes {
+		status := "missing"
+		if present[name] {
+			status = "present"
+		}
+		fmt.Printf("Index %s: %s\n", name, status)
+	}
+	fmt.Println()
+
+	profileRepo := repository.NewProfileRepository(db)
+	scenarios := []scenario{
+		{"first_name", func(rnd *rand.Rand) repositories.SearchFilters {
+			return repositories.SearchFilters{FirstName: randomPrefix(rnd, firstNames)}
+		}},
+		{"last_name", func(rnd *rand.Rand) repositories.SearchFilters {
+			return repositories.SearchFilters{LastName: randomPrefix(rnd, lastNameStems)}
+		}},
+		{"first_name+last_name", func(rnd *rand.Rand) repositories.SearchFilters {
+			return repositories.SearchFilters{
+				FirstName: randomPrefix(rnd, firstNames),
+				LastName:  randomPrefix(rnd, lastNameStems),
+			}
+		}},
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "scenario\trequests\terrors\treq/s\tp50\tp95\tp99\tmax\t")
+	for _, sc := range scenarios {
+		res := measure(ctx, profileRepo, sc)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n",
+			res.name,
+			len(res.latencies),
+			res.errors,
+			float64(len(res.latencies))/res.elapsed.Seconds(),
+			percentile(res.latencies, 0.50),
+			percentile(res.latencies, 0.95),
+			percentile(res.latencies, 0.99),
+			percentile(res.latencies, 1),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// measure выполняет сценарий параллельно в течение duration
+func measure(ctx context.Context, profileRepo repositories.ProfileRepository, sc scenario) result {
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		res = result{name: sc.name}
+	)
+
+	started := time.Now()
+	for worker := 0; worker < *concurrency; worker++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			rnd := rand.New(rand.NewSource(seed))
+			var latencies []time.Duration
+			failed := 0
+
+			for ctx.Err() == nil {
+				filters := sc.filters(rnd)
+				filters.Limit = *limit
+
+				requestStarted := time.Now()
+				_, _, err := profileRepo.Search(ctx, filters)
+				if err == nil {
+					_, err = profileRepo.Count(ctx, filters)
+				}
+				if ctx.Err() != nil {
+					break
+				}
+				if err != nil {
+					failed++
+					continue
+				}
+				latencies = append(latencies, time.Since(requestStarted))
+			}
+
+			mu.Lock()
+			res.latencies = append(res.latencies, latencies...)
+			res.errors += failed
+			mu.Unlock()
+		}(int64(worker))
+	}
+	wg.Wait()
+
+	res.elapsed = time.Since(started)
+	sort.Slice(res.latencies, func(i, j int) bool { return res.latencies[i] < res.latencies[j] })
+	return res
+}
+
+// presentIndexes возвращает, какие из измеряемых индексов есть в базе
+func presentIndexes(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT indexname FROM pg_indexes WHERE tablename = 'profiles' AND indexname = ANY($1)",
+		pq.Array(nameIndexes),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	defer rows.Close()
+
+	present := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		present[name] = true
+	}
+
+	return present, rows.Err()
+}
+
+// randomPrefix возвращает начало случайного значения длиной от 1 до 3 символов
+func randomPrefix(rnd *rand.Rand, values []string) *string {
+	runes := []rune(values[rnd.Intn(len(values))])
+	prefix := string(runes[:min(1+rnd.Intn(3), len(runes))])
+	return &prefix
+}
+
+// percentile возвращает значение перцентиля p (от 0 до 1) отсортированных замеров
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*p+0.5) - 1
+	index = max(0, min(index, len(sorted)-1))
+	return sorted[index].Round(10 * time.Microsecond)
+}
+
+func usage() {
+	fmt.Print(`Usage: go run ./cmd/searchbench [OPTIONS] COMMAND
+
+Benchmark of profile search by first and last name prefix.
+
+Commands:
+    seed              Create synthetic users with profiles (idempotent)
+    run               Measure search latency and throughput
+    drop-indexes      Drop the name prefix indexes
+    create-indexes    Recreate the name prefix indexes
+    cleanup           Delete synthetic users and their profiles
+
+To compare before and after the name indexes:
+    go run ./cmd/searchbench -profiles 1000000 seed
+    go run ./cmd/searchbench drop-indexes
+    go run ./cmd/searchbench run
+    go run ./cmd/searchbench create-indexes
+    go run ./cmd/searchbench run
+
+Options:
+`)
+	flags.PrintDefaults()
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,8 +14,17 @@ import (
 
 	_ "github.com/Spoloborota/experiment/docs" // Импорт для swagger
 	"github.com/Spoloborota/experiment/internal/config"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
 	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/infrastructure/blobstorage"
 	"github.com/Spoloborota/experiment/internal/infrastructure/database"
+	"github.com/Spoloborota/experiment/internal/infrastructure/friends"
+	"github.com/Spoloborota/experiment/internal/infrastructure/imaging"
+	"github.com/Spoloborota/experiment/internal/infrastructure/jwtkeys"
+	"github.com/Spoloborota/experiment/internal/infrastructure/mail"
+	"github.com/Spoloborota/experiment/internal/infrastructure/memory"
+	"github.com/Spoloborota/experiment/internal/infrastructure/oidc"
+	"github.com/Spoloborota/experiment/internal/infrastructure/passwordhash"
 	"github.com/Spoloborota/experiment/internal/infrastructure/repository"
 	"github.com/Spoloborota/experiment/internal/interfaces/http/routes"
 )
@@ -41,6 +51,12 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to load config", zap.Error(err))
 	}
+	if err := cfg.Validate(); err != nil {
+		logger.Fatal("Invalid config", zap.Error(err))
+	}
+	if cfg.DevMode {
+		logger.Warn("Running in development mode")
+	}
 
 	logger.Info("Starting social network server",
 		zap.String("port", cfg.Server.Port),
@@ -58,13 +74,204 @@ func main() {
 	// Инициализируем репозитории
 	userRepo := repository.NewUserRepository(db)
 	profileRepo := repository.NewProfileRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	passwordResetRepo := repository.NewPasswordResetTokenRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationTokenRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	oauthStateRepo := repository.NewOAuthStateRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	photoRepo := repository.NewProfilePhotoRepository(db)
+	interestRepo := repository.NewInterestRepository(db)
+
+	// Хранилище отозванных токенов: in-memory подходит только для одного экземпляра сервера
+	var revocationStore repositories.RevocationStore
+	switch cfg.JWT.RevocationStore {
+	case "memory":
+		revocationStore = memory.NewRevocationStore()
+	case "postgres":
+		revocationStore = repository.NewRevocationStore(db)
+	default:
+		logger.Fatal("Unknown token revocation store", zap.String("store", cfg.JWT.RevocationStore))
+	}
+
+	// Хранилище счетчиков неудачных попыток входа: in-memory не разделяется между экземплярами сервера
+	var loginAttemptStore repositories.LoginAttemptStore
+	switch cfg.Login.Store {
+	case "memory":
+		loginAttemptStore = memory.NewLoginAttemptStore()
+	case "postgres":
+		loginAttemptStore = repository.NewLoginAttemptStore(db)
+	default:
+		logger.Fatal("Unknown login throttle store", zap.String("store", cfg.Login.Store))
+	}
+
+	// Настраиваем отправку писем
+	var mailer services.MailSender
+	switch cfg.Mail.Sender {
+	case "log":
+		mailer = mail.NewLogSender(logger)
+	case "file":
+		mailer, err = mail.NewFileSender(cfg.Mail.FileDir, cfg.Mail.From)
+		if err != nil {
+			logger.Fatal("Failed to initialize mail sender", zap.Error(err))
+		}
+	default:
+		logger.Fatal("Unknown mail sender", zap.String("sender", cfg.Mail.Sender))
+	}
+
+	// Настраиваем хранилище файлов. Локальное хранилище раздается самим сервером,
+	// если STORAGE_PUBLIC_URL задан путем, а не адресом внешнего веб-сервера
+	var blobStorage services.BlobStorage
+	var mediaHandler http.Handler
+	switch cfg.Storage.Backend {
+	case "local":
+		localStorage, err := blobstorage.NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.PublicURL)
+		if err != nil {
+			logger.Fatal("Failed to initialize file storage", zap.Error(err))
+		}
+		blobStorage = localStorage
+		if strings.HasPrefix(cfg.Storage.PublicURL, "/") {
+			mediaHandler = localStorage.Handler()
+		}
+	default:
+		logger.Fatal("Unknown file storage backend", zap.String("backend", cfg.Storage.Backend))
+	}
+
+	// Загружаем ключи подписи JWT
+	keys, err := jwtkeys.Load(cfg.JWT)
+	if err != nil {
+		logger.Fatal("Failed to load JWT keys", zap.Error(err))
+	}
+
+	// Настраиваем хеширование паролей и политику для новых паролей
+	passwordHasher, err := passwordhash.Load(cfg.Hashing)
+	if err != nil {
+		logger.Fatal("Failed to configure password hashing", zap.Error(err))
+	}
+	breachedPasswords, err := passwordhash.LoadBreachedPasswords(cfg.Policy.BreachedListFile)
+	if err != nil {
+		logger.Fatal("Failed to load breached password list", zap.Error(err))
+	}
+	passwords := services.NewPasswordManager(
+		passwordHasher,
+		services.NewPasswordPolicy(cfg.Policy.MinLength, breachedPasswords),
+	)
 
 	// Инициализируем сервисы
-	authService := services.NewAuthService(userRepo, cfg.JWT.Secret, cfg.JWT.ExpiryHours)
-	profileService := services.NewProfileService(profileRepo)
+	twoFactorService := services.NewTwoFactorService(userRepo, twoFactorRepo, cfg.TOTP.Issuer)
+	loginThrottler := services.NewLoginThrottler(loginAttemptStore, services.LoginThrottlePolicy{
+		FreeAttempts:            cfg.Login.FreeAttempts,
+		BaseDelay:               time.Second,
+		MaxDelay:                time.Duration(cfg.Login.MaxDelaySeconds) * time.Second,
+		AccountLockoutThreshold: cfg.Login.AccountLockoutThreshold,
+		IPLockoutThreshold:      cfg.Login.IPLockoutThreshold,
+		LockoutDuration:         time.Duration(cfg.Login.LockoutMinutes) * time.Minute,
+	})
+	authService := services.NewAuthService(
+		userRepo,
+		refreshTokenRepo,
+		sessionRepo,
+		revocationStore,
+		keys,
+		passwords,
+		twoFactorService,
+		loginThrottler,
+		time.Duration(cfg.JWT.AccessExpiryMinutes)*time.Minute,
+		time.Duration(cfg.JWT.RefreshExpiryHours)*time.Hour,
+	)
+	photoService := services.NewPhotoService(
+		profileRepo,
+		photoRepo,
+		blobStorage,
+		imaging.NewProcessor(),
+		int64(cfg.Photos.MaxSizeMB)<<20,
+		cfg.Photos.MaxPerProfile,
+	)
+	interestService := services.NewInterestService(interestRepo)
+	profileService := services.NewProfileService(profileRepo, photoService, interestService, friends.NewNoFriends())
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo)
+
+	identityProviders := make([]services.IdentityProvider, 0, len(cfg.OAuth.Providers))
+	for _, providerCfg := range cfg.OAuth.Providers {
+		identityProviders = append(identityProviders, oidc.New(providerCfg))
+	}
+	oauthService := services.NewOAuthService(
+		userRepo,
+		identityRepo,
+		oauthStateRepo,
+		authService,
+		time.Duration(cfg.OAuth.StateTTLMinutes)*time.Minute,
+		identityProviders...,
+	)
+	auditService := services.NewAuditService(auditLogRepo)
+	adminService := services.NewAdminService(
+		userRepo,
+		profileRepo,
+		photoService,
+		interestService,
+		authService,
+		auditService,
+		time.Duration(cfg.Admin.ImpersonationTTLMinutes)*time.Minute,
+	)
+	accountService := services.NewAccountService(
+		userRepo,
+		authService,
+		time.Duration(cfg.Account.DeletionGraceDays)*24*time.Hour,
+		profileService,
+		photoService,
+		twoFactorService,
+		apiKeyService,
+		authService,
+		oauthService,
+	)
+	passwordResetService := services.NewPasswordResetService(
+		userRepo,
+		passwordResetRepo,
+		authService,
+		mailer,
+		// Запросы сброса ограничиваются по тем же ключам аккаунта и IP, что и вход, но отдельными счетчиками
+		loginThrottler.WithNamespace("password_reset", services.LoginThrottlePolicy{
+			FreeAttempts:            2,
+			BaseDelay:               time.Minute,
+			MaxDelay:                time.Duration(cfg.Login.LockoutMinutes) * time.Minute,
+			AccountLockoutThreshold: 5,
+			IPLockoutThreshold:      cfg.Login.IPLockoutThreshold,
+			LockoutDuration:         time.Duration(cfg.Login.LockoutMinutes) * time.Minute,
+		}),
+		time.Duration(cfg.Password.ResetTTLMinutes)*time.Minute,
+		cfg.Password.ResetURL,
+	)
+	emailVerificationService := services.NewEmailVerificationService(
+		userRepo,
+		emailVerificationRepo,
+		mailer,
+		time.Duration(cfg.Email.TokenTTLHours)*time.Hour,
+		cfg.Email.VerifyURL,
+	)
 
 	// Настраиваем роуты
-	router := routes.NewRoutes(authService, profileService, logger)
+	router := routes.NewRoutes(routes.Services{
+		Auth:              authService,
+		Profile:           profileService,
+		Photos:            photoService,
+		Interests:         interestService,
+		Account:           accountService,
+		Admin:             adminService,
+		Audit:             auditService,
+		PasswordReset:     passwordResetService,
+		EmailVerification: emailVerificationService,
+		TwoFactor:         twoFactorService,
+		APIKeys:           apiKeyService,
+		OAuth:             oauthService,
+		Keys:              keys,
+	}, routes.Options{
+		RequireVerifiedEmail: cfg.Email.Required,
+		MediaPath:            cfg.Storage.PublicURL,
+		MediaHandler:         mediaHandler,
+	}, logger)
 	handler := router.Setup()
 
 	// Создаем HTTP сервер
@@ -76,6 +283,62 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Фоновые задачи останавливаются вместе с сервером
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Периодически удаляем устаревшие счетчики неудачных попыток входа
+	go runPeriodically(backgroundCtx, 10*time.Minute, func(ctx context.Context) {
+		if err := loginThrottler.PurgeStale(ctx); err != nil {
+			logger.Error("Failed to purge stale login attempts", zap.Error(err))
+		}
+	})
+
+	// Удаляем истекшие и завершенные сессии
+	go runPeriodically(backgroundCtx, time.Hour, func(ctx context.Context) {
+		if err := authService.PurgeStaleSessions(ctx); err != nil {
+			logger.Error("Failed to purge stale sessions", zap.Error(err))
+		}
+	})
+
+	// Отправляем письма сброса пароля из очереди запросов
+	go passwordResetService.Run(backgroundCtx, func(err error) {
+		logger.Error("Failed to request password reset", zap.Error(err))
+	})
+
+	// Удаляем истекшие refresh токены и отзывы истекших access токенов
+	go runPeriodically(backgroundCtx, time.Hour, func(ctx context.Context) {
+		if err := authService.PurgeExpiredTokens(ctx); err != nil {
+			logger.Error("Failed to purge expired tokens", zap.Error(err))
+		}
+	})
+
+	// Удаляем состояния незавершенных входов через внешних провайдеров
+	go runPeriodically(backgroundCtx, time.Hour, func(ctx context.Context) {
+		if err := oauthService.PurgeExpiredStates(ctx); err != nil {
+			logger.Error("Failed to purge expired OAuth states", zap.Error(err))
+		}
+	})
+
+	// Удаляем файлы фотографий, удаленных из анкет или оставшихся от удаленных анкет
+	go runPeriodically(backgroundCtx, time.Hour, func(ctx context.Context) {
+		if err := photoService.PurgeDetachedPhotos(ctx); err != nil {
+			logger.Error("Failed to purge detached photos", zap.Error(err))
+		}
+	})
+
+	// Окончательно удаляем аккаунты, срок хранения которых истек
+	go runPeriodically(backgroundCtx, time.Hour, func(ctx context.Context) {
+		purged, err := accountService.PurgeDeletedAccounts(ctx)
+		if err != nil {
+			logger.Error("Failed to purge deleted accounts", zap.Error(err))
+			return
+		}
+		if purged > 0 {
+			logger.Info("Purged deleted accounts", zap.Int64("count", purged))
+		}
+	})
+
 	// Канал для graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -94,6 +357,7 @@ func main() {
 	// Ждем сигнал для остановки
 	<-done
 	logger.Info("Server is shutting down...")
+	stopBackground()
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -105,3 +369,18 @@ func main() {
 
 	logger.Info("Server exited properly")
 }
+
+// runPeriodically выполняет task с интервалом interval, пока не отменен ctx
+func runPeriodically(ctx context.Context, interval time.Duration, task func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			task(ctx)
+		}
+	}
+}
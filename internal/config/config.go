@@ -1,16 +1,35 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// defaultJWTSecret используется только для локальной разработки
+const defaultJWTSecret = "your-secret-key"
+
 type Config struct {
+	DevMode  bool
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Mail     MailConfig
+	Password PasswordResetConfig
+	Email    EmailVerificationConfig
+	TOTP     TOTPConfig
+	Login    LoginThrottleConfig
+	Account  AccountConfig
+	Hashing  PasswordHashConfig
+	Policy   PasswordPolicyConfig
+	OAuth    OAuthConfig
+	Admin    AdminConfig
+	Storage  StorageConfig
+	Photos   PhotoConfig
 }
 
 type ServerConfig struct {
@@ -28,8 +47,91 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Algorithm            string // HS256, RS256 или EdDSA
+	Secret               string
+	SigningKeyFile       string   // PEM с приватным ключом для RS256/EdDSA
+	SigningKeyID         string   // kid текущего ключа подписи
+	VerificationKeyFiles []string // Публичные ключи для проверки в формате kid=path или path
+	AccessExpiryMinutes  int
+	RefreshExpiryHours   int
+	RevocationStore      string // postgres или memory
+}
+
+type MailConfig struct {
+	Sender  string // log или file
+	FileDir string // Каталог для писем при Sender=file
+	From    string
+}
+
+type PasswordResetConfig struct {
+	ResetURL        string // Страница клиента, на которую ведет ссылка из письма
+	ResetTTLMinutes int
+}
+
+type EmailVerificationConfig struct {
+	VerifyURL     string // Страница клиента, на которую ведет ссылка из письма
+	TokenTTLHours int
+	Required      bool // Запрещать неподтвержденным пользователям создавать анкету и искать
+}
+
+type TOTPConfig struct {
+	Issuer string // Название сервиса в приложении-аутентификаторе
+}
+
+type LoginThrottleConfig struct {
+	Store                   string // postgres или memory
+	FreeAttempts            int    // Неудачные попытки без задержки
+	MaxDelaySeconds         int    // Предел прогрессивной задержки
+	AccountLockoutThreshold int
+	IPLockoutThreshold      int
+	LockoutMinutes          int
+}
+
+type AccountConfig struct {
+	DeletionGraceDays int // Сколько дней удаленный аккаунт хранится до окончательной очистки; 0 - после истечения access токенов
+}
+
+type PasswordHashConfig struct {
+	Algorithm         string // argon2id или bcrypt; хеши другого алгоритма пересчитываются при входе
+	Argon2MemoryKB    int
+	Argon2Iterations  int
+	Argon2Parallelism int
+	BcryptCost        int
+}
+
+type PasswordPolicyConfig struct {
+	MinLength        int
+	BreachedListFile string // Файл с паролями из утечек, по одному в строке
+}
+
+type OAuthConfig struct {
+	StateTTLMinutes int // Сколько ждать возврата пользователя со страницы входа провайдера
+	Providers       []OAuthProviderConfig
+}
+
+// OAuthProviderConfig описывает OIDC провайдера. Адреса endpoint'ов берутся из discovery документа издателя
+type OAuthProviderConfig struct {
+	Name         string // Имя провайдера в адресах API: /api/v1/oauth/{name}/...
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // Адрес, на который провайдер возвращает пользователя с кодом авторизации
+	Scopes       []string
+}
+
+type AdminConfig struct {
+	ImpersonationTTLMinutes int // Срок действия токена администратора для входа под пользователем
+}
+
+type StorageConfig struct {
+	Backend   string // local - файлы хранятся на диске
+	LocalDir  string // Каталог для файлов при Backend=local
+	PublicURL string // Адрес, по которому клиенты получают файлы; путь вида /media раздается самим сервером
+}
+
+type PhotoConfig struct {
+	MaxSizeMB     int // Максимальный размер загружаемого файла
+	MaxPerProfile int // Сколько фотографий можно загрузить в одну анкету
 }
 
 func Load() (*Config, error) {
@@ -37,6 +139,7 @@ func Load() (*Config, error) {
 	_ = godotenv.Load()
 
 	cfg := &Config{
+		DevMode: getEnvAsBool("DEV_MODE", false),
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "8080"),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
@@ -50,14 +153,115 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "your-secret-key"),
-			ExpiryHours: getEnvAsInt("JWT_EXPIRY_HOURS", 24),
+			Algorithm:            getEnv("JWT_ALGORITHM", "HS256"),
+			Secret:               getEnv("JWT_SECRET", defaultJWTSecret),
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			SigningKeyID:         getEnv("JWT_SIGNING_KEY_ID", ""),
+			VerificationKeyFiles: getEnvAsList("JWT_VERIFICATION_KEY_FILES"),
+			AccessExpiryMinutes:  getEnvAsInt("JWT_ACCESS_EXPIRY_MINUTES", 15),
+			RefreshExpiryHours:   getEnvAsInt("JWT_REFRESH_EXPIRY_HOURS", 720),
+			RevocationStore:      getEnv("JWT_REVOCATION_STORE", "postgres"),
+		},
+		Mail: MailConfig{
+			Sender:  getEnv("MAIL_SENDER", "log"),
+			FileDir: getEnv("MAIL_FILE_DIR", "tmp/mail"),
+			From:    getEnv("MAIL_FROM", "no-reply@localhost"),
+		},
+		Password: PasswordResetConfig{
+			ResetURL:        getEnv("PASSWORD_RESET_URL", "http://localhost:8080/reset-password"),
+			ResetTTLMinutes: getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 60),
+		},
+		Email: EmailVerificationConfig{
+			VerifyURL:     getEnv("EMAIL_VERIFY_URL", "http://localhost:8080/verify-email"),
+			TokenTTLHours: getEnvAsInt("EMAIL_VERIFY_TTL_HOURS", 48),
+			Required:      getEnvAsBool("REQUIRE_VERIFIED_EMAIL", false),
+		},
+		TOTP: TOTPConfig{
+			Issuer: getEnv("TOTP_ISSUER", "Social Network"),
+		},
+		Login: LoginThrottleConfig{
+			Store:                   getEnv("LOGIN_THROTTLE_STORE", "postgres"),
+			FreeAttempts:            getEnvAsInt("LOGIN_FREE_ATTEMPTS", 3),
+			MaxDelaySeconds:         getEnvAsInt("LOGIN_MAX_DELAY_SECONDS", 60),
+			AccountLockoutThreshold: getEnvAsInt("LOGIN_ACCOUNT_LOCKOUT_THRESHOLD", 10),
+			IPLockoutThreshold:      getEnvAsInt("LOGIN_IP_LOCKOUT_THRESHOLD", 100),
+			LockoutMinutes:          getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15),
+		},
+		Account: AccountConfig{
+			DeletionGraceDays: getEnvAsInt("ACCOUNT_DELETION_GRACE_DAYS", 30),
+		},
+		Hashing: PasswordHashConfig{
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			Argon2MemoryKB:    getEnvAsInt("PASSWORD_ARGON2_MEMORY_KB", 19456),
+			Argon2Iterations:  getEnvAsInt("PASSWORD_ARGON2_ITERATIONS", 2),
+			Argon2Parallelism: getEnvAsInt("PASSWORD_ARGON2_PARALLELISM", 1),
+			BcryptCost:        getEnvAsInt("PASSWORD_BCRYPT_COST", 10),
+		},
+		Policy: PasswordPolicyConfig{
+			MinLength:        getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			BreachedListFile: getEnv("PASSWORD_BREACHED_LIST_FILE", ""),
+		},
+		OAuth: OAuthConfig{
+			StateTTLMinutes: getEnvAsInt("OAUTH_STATE_TTL_MINUTES", 10),
+			Providers:       loadOAuthProviders(),
+		},
+		Admin: AdminConfig{
+			ImpersonationTTLMinutes: getEnvAsInt("IMPERSONATION_TTL_MINUTES", 15),
+		},
+		Storage: StorageConfig{
+			Backend:   getEnv("STORAGE_BACKEND", "local"),
+			LocalDir:  getEnv("STORAGE_LOCAL_DIR", "tmp/media"),
+			PublicURL: getEnv("STORAGE_PUBLIC_URL", "/media"),
+		},
+		Photos: PhotoConfig{
+			MaxSizeMB:     getEnvAsInt("PHOTO_MAX_SIZE_MB", 10),
+			MaxPerProfile: getEnvAsInt("PHOTO_MAX_PER_PROFILE", 10),
 		},
 	}
 
 	return cfg, nil
 }
 
+// Validate проверяет конфигурацию перед запуском сервера
+func (c *Config) Validate() error {
+	if c.JWT.Algorithm == "HS256" && c.JWT.Secret == defaultJWTSecret && !c.DevMode {
+		return errors.New("JWT_SECRET must be changed from the default value (set DEV_MODE=true for local development)")
+	}
+
+	for _, provider := range c.OAuth.Providers {
+		if provider.IssuerURL == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return fmt.Errorf("OAuth provider %s requires issuer URL, client ID and redirect URL", provider.Name)
+		}
+	}
+
+	return nil
+}
+
+// loadOAuthProviders читает настройки провайдеров, перечисленных в OAUTH_PROVIDERS.
+// Для провайдера google используются переменные OAUTH_GOOGLE_ISSUER_URL, OAUTH_GOOGLE_CLIENT_ID и т.д.
+func loadOAuthProviders() []OAuthProviderConfig {
+	var providers []OAuthProviderConfig
+	for _, name := range getEnvAsList("OAUTH_PROVIDERS") {
+		name = strings.ToLower(name)
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+
+		scopes := getEnvAsList(prefix + "SCOPES")
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+
+		providers = append(providers, OAuthProviderConfig{
+			Name:         name,
+			IssuerURL:    getEnv(prefix+"ISSUER_URL", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       scopes,
+		})
+	}
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -74,6 +278,25 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func (c *Config) DatabaseURL() string {
 	return "postgres://" + c.Database.User + ":" + c.Database.Password +
 		"@" + c.Database.Host + ":" + c.Database.Port +
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Области доступа API ключей
+const (
+	ScopeProfilesRead  = "profiles:read"
+	ScopeProfilesWrite = "profiles:write"
+)
+
+// knownScopes содержит все области доступа, которые можно выдать ключу
+var knownScopes = map[string]bool{
+	ScopeProfilesRead:  true,
+	ScopeProfilesWrite: true,
+}
+
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Начало ключа, чтобы отличать ключи в списке
+	KeyHash    string     `json:"-"`      // Храним только хеш ключа
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAPIKey создает API ключ с валидацией имени, областей доступа и срока действия
+func NewAPIKey(userID int, name, prefix, keyHash string, scopes []string, expiresAt *time.Time) (*APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("api key name is required")
+	}
+	if len(name) > 100 {
+		return nil, errors.New("api key name must be at most 100 characters long")
+	}
+
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !knownScopes[scope] {
+			return nil, errors.New("unknown scope: " + scope)
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	return &APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// IsUsable проверяет, что ключ не отозван и не истек
+func (k *APIKey) IsUsable() bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt))
+}
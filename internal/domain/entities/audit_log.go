@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	// AuditActionImpersonationStart - администратор получил токен для входа под пользователем
+	AuditActionImpersonationStart = "impersonation.start"
+	// AuditActionImpersonatedRequest - запрос, выполненный администратором от имени пользователя
+	AuditActionImpersonatedRequest = "impersonation.request"
+)
+
+// AuditLogEntry описывает действие администратора от имени пользователя
+type AuditLogEntry struct {
+	ID        int64     `json:"id"`
+	ActorID   int       `json:"actor_id"` // Администратор, выполнивший действие
+	UserID    int       `json:"user_id"`  // Пользователь, от имени которого выполнено действие
+	Action    string    `json:"action"`
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Status    int       `json:"status,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DateLayout - формат даты без времени в API
+const DateLayout = "2006-01-02"
+
+// Date - календарная дата без времени и часового пояса, в JSON записывается как "2006-01-02"
+type Date struct {
+	time.Time
+}
+
+// NewDate создает дату из года, месяца и дня
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf возвращает календарную дату момента t в его часовом поясе
+func DateOf(t time.Time) Date {
+	return NewDate(t.Date())
+}
+
+// ParseDate разбирает дату в формате "2006-01-02"
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return Date{}, errors.New("date must be in YYYY-MM-DD format")
+	}
+	return Date{Time: t}, nil
+}
+
+// String возвращает дату в формате "2006-01-02"
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// YearsAt возвращает число полных лет, прошедших от даты до on. Для 29 февраля
+// в невисокосный год годовщина наступает 1 марта
+func (d Date) YearsAt(on Date) int {
+	years := on.Year() - d.Year()
+	if on.Month() < d.Month() || (on.Month() == d.Month() && on.Day() < d.Day()) {
+		years--
+	}
+	return years
+}
+
+// LatestBirthDate возвращает самую позднюю дату рождения, при которой к дате on исполнилось
+// не меньше age полных лет. Согласована с YearsAt: d.YearsAt(on) >= age, только если d не позже результата
+func LatestBirthDate(age int, on Date) Date {
+	born := NewDate(on.Year()-age, on.Month(), on.Day())
+	if born.Day() != on.Day() {
+		// 29 февраля в невисокосный год: годовщина рожденных 1 марта еще не наступила
+		born = NewDate(on.Year()-age, on.Month(), on.Day()-1)
+	}
+	return born
+}
+
+// MarshalJSON записывает дату в формате "2006-01-02"
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON читает дату в формате "2006-01-02"
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.New("date must be a string in YYYY-MM-DD format")
+	}
+
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+)
+
+type EmailVerificationToken struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Email     string     `json:"email"` // Адрес, владение которым подтверждает токен
+	TokenHash string     `json:"-"`     // Храним только хеш токена
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewEmailVerificationToken создает новый токен подтверждения email
+func NewEmailVerificationToken(userID int, email, tokenHash string, ttl time.Duration) *EmailVerificationToken {
+	now := time.Now()
+	return &EmailVerificationToken{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsUsable проверяет, что токен еще не использован и не истек
+func (t *EmailVerificationToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxInterestLength - максимальная длина названия интереса в символах
+const MaxInterestLength = 50
+
+// Interest - канонический интерес из таксономии. В анкетах и фильтрах поиска интересы
+// задаются slug, названием на любом языке или синонимом
+type Interest struct {
+	ID            int    `json:"-"`
+	Slug          string `json:"slug"`
+	Label         string `json:"label"`                    // Название на запрошенном языке
+	ProfilesCount int    `json:"profiles_count,omitempty"` // Количество анкет с этим интересом
+}
+
+// NormalizeInterest приводит название интереса к виду, в котором хранятся синонимы:
+// нижний регистр, одиночные пробелы, ё заменяется на е. Должна совпадать с SQL функцией normalize_interest
+func NormalizeInterest(name string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	return strings.ReplaceAll(normalized, "ё", "е")
+}
+
+// InterestSlug возвращает slug нового интереса по нормализованному названию
+func InterestSlug(normalized string) string {
+	return strings.ReplaceAll(normalized, " ", "-")
+}
+
+// InterestLocale определяет язык названия нового интереса: ru для кириллицы, иначе en
+func InterestLocale(name string) string {
+	for _, r := range name {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return "ru"
+		}
+	}
+	return "en"
+}
@@ -0,0 +1,12 @@
+package entities
+
+import (
+	"time"
+)
+
+// LoginAttempts хранит счетчик неудачных попыток входа по ключу (аккаунт или IP адрес)
+type LoginAttempts struct {
+	Key           string    `json:"key"`
+	Failures      int       `json:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+}
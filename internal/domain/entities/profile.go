@@ -2,23 +2,41 @@ package entities
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// Profile - анкета пользователя. Поля, скрытые от зрителя настройками видимости,
+// не попадают в ответ API (см. ViewFor)
 type Profile struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Age       int       `json:"age"`
-	Gender    string    `json:"gender"`
-	City      string    `json:"city"`
-	Interests []string  `json:"interests"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int             `json:"id"`
+	UserID    int             `json:"user_id"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	BirthDate Date            `json:"birth_date,omitzero"` // Полная дата рождения видна только владельцу
+	Age       int             `json:"age,omitempty"`       // Вычисляется по дате рождения при чтении
+	Birthday  string          `json:"birthday,omitempty"`  // День рождения без года в формате MM-DD
+	Zodiac    ZodiacSign      `json:"zodiac_sign,omitempty"`
+	Gender    string          `json:"gender,omitempty"`
+	City      string          `json:"city,omitempty"`
+	Interests []string        `json:"interests,omitempty"` // Slug канонических интересов в порядке, заданном владельцем
+	HiddenAt  *time.Time      `json:"hidden_at,omitempty"` // Анкета скрыта модератором
+	Version   int             `json:"version"`             // Увеличивается при каждом изменении анкеты
+	Photos    []*ProfilePhoto `json:"photos,omitempty"`    // Фотографии в порядке показа
+	Privacy   *ProfilePrivacy `json:"privacy,omitempty"`   // Настройки видимости, видны только владельцу
+	Match     *SearchMatch    `json:"match,omitempty"`     // Совпадение с текстовым запросом, только в результатах поиска
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
+// MinAge и MaxAge - допустимый возраст владельца анкеты
+const (
+	MinAge = 16
+	MaxAge = 120
+)
+
 type Gender string
 
 const (
@@ -28,36 +46,41 @@ const (
 )
 
 // NewProfile создает новый профиль с валидацией
-func NewProfile(userID int, firstName, lastName string, age int, gender string, city string, interests []string) (*Profile, error) {
-	if err := validateProfileData(firstName, lastName, age, gender); err != nil {
+func NewProfile(userID int, firstName, lastName string, birthDate Date, gender string, city string, interests []string) (*Profile, error) {
+	if err := validateProfileData(firstName, lastName, birthDate, gender, interests); err != nil {
 		return nil, err
 	}
 
 	// Очищаем и нормализуем данные
 	cleanInterests := cleanInterests(interests)
 
-	return &Profile{
+	privacy := DefaultProfilePrivacy()
+
+	profile := &Profile{
 		UserID:    userID,
 		FirstName: strings.TrimSpace(firstName),
 		LastName:  strings.TrimSpace(lastName),
-		Age:       age,
 		Gender:    strings.ToLower(gender),
 		City:      strings.TrimSpace(city),
 		Interests: cleanInterests,
+		Privacy:   &privacy,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
-	}, nil
+	}
+	profile.SetBirthDate(birthDate)
+
+	return profile, nil
 }
 
 // Update обновляет профиль с валидацией
-func (p *Profile) Update(firstName, lastName string, age int, gender string, city string, interests []string) error {
-	if err := validateProfileData(firstName, lastName, age, gender); err != nil {
+func (p *Profile) Update(firstName, lastName string, birthDate Date, gender string, city string, interests []string) error {
+	if err := validateProfileData(firstName, lastName, birthDate, gender, interests); err != nil {
 		return err
 	}
 
 	p.FirstName = strings.TrimSpace(firstName)
 	p.LastName = strings.TrimSpace(lastName)
-	p.Age = age
+	p.SetBirthDate(birthDate)
 	p.Gender = strings.ToLower(gender)
 	p.City = strings.TrimSpace(city)
 	p.Interests = cleanInterests(interests)
@@ -66,13 +89,70 @@ func (p *Profile) Update(firstName, lastName string, age int, gender string, cit
 	return nil
 }
 
+// SetBirthDate устанавливает дату рождения и вычисляет по ней возраст на сегодня,
+// день рождения и знак зодиака
+func (p *Profile) SetBirthDate(birthDate Date) {
+	p.BirthDate = birthDate
+	p.Age, p.Birthday, p.Zodiac = 0, "", ""
+	if birthDate.IsZero() {
+		return
+	}
+
+	p.Age = birthDate.YearsAt(DateOf(time.Now()))
+	p.Birthday = birthDate.Format("01-02")
+	p.Zodiac = birthDate.ZodiacSign()
+}
+
+// ProfilePatch описывает частичное обновление анкеты: поля со значением nil не меняются.
+// Пустые город и список интересов очищают соответствующие поля
+type ProfilePatch struct {
+	FirstName *string
+	LastName  *string
+	BirthDate *Date
+	Gender    *string
+	City      *string
+	Interests *[]string
+}
+
+// IsEmpty проверяет, что обновление не затрагивает ни одного поля
+func (p ProfilePatch) IsEmpty() bool {
+	return p.FirstName == nil && p.LastName == nil && p.BirthDate == nil &&
+		p.Gender == nil && p.City == nil && p.Interests == nil
+}
+
+// ApplyPatch применяет частичное обновление. Итоговая анкета проверяется целиком,
+// поэтому при ошибке профиль не меняется
+func (p *Profile) ApplyPatch(patch ProfilePatch) error {
+	firstName, lastName, birthDate, gender, city, interests := p.FirstName, p.LastName, p.BirthDate, p.Gender, p.City, p.Interests
+	if patch.FirstName != nil {
+		firstName = *patch.FirstName
+	}
+	if patch.LastName != nil {
+		lastName = *patch.LastName
+	}
+	if patch.BirthDate != nil {
+		birthDate = *patch.BirthDate
+	}
+	if patch.Gender != nil {
+		gender = *patch.Gender
+	}
+	if patch.City != nil {
+		city = *patch.City
+	}
+	if patch.Interests != nil {
+		interests = *patch.Interests
+	}
+
+	return p.Update(firstName, lastName, birthDate, gender, city, interests)
+}
+
 // GetFullName возвращает полное имя
 func (p *Profile) GetFullName() string {
 	return p.FirstName + " " + p.LastName
 }
 
 // validateProfileData проверяет корректность данных профиля
-func validateProfileData(firstName, lastName string, age int, gender string) error {
+func validateProfileData(firstName, lastName string, birthDate Date, gender string, interests []string) error {
 	if strings.TrimSpace(firstName) == "" {
 		return errors.New("first name cannot be empty")
 	}
@@ -81,8 +161,12 @@ func validateProfileData(firstName, lastName string, age int, gender string) err
 		return errors.New("last name cannot be empty")
 	}
 
-	if age < 16 || age > 120 {
-		return errors.New("age must be between 16 and 120")
+	if birthDate.IsZero() {
+		return errors.New("birth date is required")
+	}
+
+	if age := birthDate.YearsAt(DateOf(time.Now())); age < MinAge || age > MaxAge {
+		return fmt.Errorf("age must be between %d and %d", MinAge, MaxAge)
 	}
 
 	validGenders := map[string]bool{
@@ -95,19 +179,27 @@ func validateProfileData(firstName, lastName string, age int, gender string) err
 		return errors.New("invalid gender value")
 	}
 
+	for _, interest := range interests {
+		if utf8.RuneCountInString(strings.TrimSpace(interest)) > MaxInterestLength {
+			return fmt.Errorf("interest must be at most %d characters", MaxInterestLength)
+		}
+	}
+
 	return nil
 }
 
-// cleanInterests очищает и нормализует список интересов
+// cleanInterests очищает список интересов и убирает повторы без учета регистра.
+// Сопоставление с таксономией выполняет InterestService
 func cleanInterests(interests []string) []string {
 	var cleaned []string
 	seen := make(map[string]bool)
 
 	for _, interest := range interests {
 		trimmed := strings.TrimSpace(interest)
-		if trimmed != "" && !seen[trimmed] {
+		key := NormalizeInterest(trimmed)
+		if trimmed != "" && !seen[key] {
 			cleaned = append(cleaned, trimmed)
-			seen[trimmed] = true
+			seen[key] = true
 		}
 	}
 
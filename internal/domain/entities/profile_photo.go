@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+)
+
+// PhotoVariantOriginal - имя варианта с исходным изображением (перекодированным без метаданных)
+const PhotoVariantOriginal = "original"
+
+// PhotoThumbnail описывает уменьшенную копию фотографии
+type PhotoThumbnail struct {
+	Name    string
+	MaxSide int // Наибольшая сторона в пикселях; изображения меньше этого размера не увеличиваются
+}
+
+// PhotoThumbnails - уменьшенные копии, которые создаются для каждой фотографии
+var PhotoThumbnails = []PhotoThumbnail{
+	{Name: "small", MaxSide: 160},
+	{Name: "medium", MaxSide: 480},
+	{Name: "large", MaxSide: 1080},
+}
+
+// ProfilePhoto описывает фотографию анкеты
+type ProfilePhoto struct {
+	ID          int               `json:"id"`
+	ProfileID   int               `json:"-"`
+	StorageKey  string            `json:"-"` // Префикс ключей вариантов в хранилище
+	ContentType string            `json:"content_type"`
+	SizeBytes   int64             `json:"size_bytes"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Position    int               `json:"position"`
+	IsPrimary   bool              `json:"is_primary"`
+	URLs        map[string]string `json:"urls"` // Адреса исходного изображения и уменьшенных копий по именам вариантов
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// VariantKey возвращает ключ варианта фотографии в хранилище.
+// Исходное изображение хранится в загруженном формате, уменьшенные копии - в JPEG
+func (p *ProfilePhoto) VariantKey(variant string) string {
+	if variant == PhotoVariantOriginal && p.ContentType == "image/png" {
+		return p.StorageKey + "/" + variant + ".png"
+	}
+	return p.StorageKey + "/" + variant + ".jpg"
+}
+
+// VariantKeys возвращает ключи всех вариантов фотографии в хранилище
+func (p *ProfilePhoto) VariantKeys() []string {
+	keys := []string{p.VariantKey(PhotoVariantOriginal)}
+	for _, thumbnail := range PhotoThumbnails {
+		keys = append(keys, p.VariantKey(thumbnail.Name))
+	}
+	return keys
+}
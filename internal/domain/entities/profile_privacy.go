@@ -0,0 +1,182 @@
+package entities
+
+import (
+	"errors"
+)
+
+// ErrInvalidVisibility возвращается, если в настройках видимости указано неизвестное значение
+var ErrInvalidVisibility = errors.New("visibility must be one of public, registered, friends, private")
+
+// Visibility определяет, кому видна анкета или ее поле
+type Visibility string
+
+const (
+	VisibilityPublic     Visibility = "public"     // Всем, включая неавторизованных
+	VisibilityRegistered Visibility = "registered" // Авторизованным пользователям
+	VisibilityFriends    Visibility = "friends"    // Друзьям владельца
+	VisibilityPrivate    Visibility = "private"    // Только владельцу
+)
+
+// ViewerRelation описывает, кем зритель приходится владельцу анкеты. Значения упорядочены
+// по возрастанию доступа: каждому следующему видно все, что видно предыдущему
+type ViewerRelation int
+
+const (
+	ViewerAnonymous ViewerRelation = iota
+	ViewerRegistered
+	ViewerFriend
+	ViewerOwner
+)
+
+// String возвращает название отношения
+func (r ViewerRelation) String() string {
+	switch r {
+	case ViewerRegistered:
+		return "registered"
+	case ViewerFriend:
+		return "friend"
+	case ViewerOwner:
+		return "owner"
+	default:
+		return "anonymous"
+	}
+}
+
+// IsValid проверяет, что значение видимости известно
+func (v Visibility) IsValid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityRegistered, VisibilityFriends, VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// VisibleTo проверяет, видно ли поле с такой видимостью зрителю
+func (v Visibility) VisibleTo(relation ViewerRelation) bool {
+	switch v {
+	case VisibilityPublic:
+		return true
+	case VisibilityRegistered:
+		return relation >= ViewerRegistered
+	case VisibilityFriends:
+		return relation >= ViewerFriend
+	default:
+		return relation == ViewerOwner
+	}
+}
+
+// ProfilePrivacy содержит настройки видимости анкеты. Profile скрывает анкету целиком,
+// остальные поля - соответствующие поля анкеты. Имя и фамилия видны всем, кому видна анкета,
+// а полная дата рождения - только владельцу
+type ProfilePrivacy struct {
+	Profile   Visibility `json:"profile"`
+	Age       Visibility `json:"age"`
+	Birthday  Visibility `json:"birthday"`
+	Zodiac    Visibility `json:"zodiac_sign"`
+	Gender    Visibility `json:"gender"`
+	City      Visibility `json:"city"`
+	Interests Visibility `json:"interests"`
+	Photos    Visibility `json:"photos"`
+}
+
+// DefaultProfilePrivacy возвращает настройки новой анкеты: все видно всем,
+// а день рождения и знак зодиака владелец показывает по желанию
+func DefaultProfilePrivacy() ProfilePrivacy {
+	return ProfilePrivacy{
+		Profile:   VisibilityPublic,
+		Age:       VisibilityPublic,
+		Birthday:  VisibilityPrivate,
+		Zodiac:    VisibilityPrivate,
+		Gender:    VisibilityPublic,
+		City:      VisibilityPublic,
+		Interests: VisibilityPublic,
+		Photos:    VisibilityPublic,
+	}
+}
+
+// ProfilePrivacyPatch описывает изменение настроек видимости: поля со значением nil не меняются
+type ProfilePrivacyPatch struct {
+	Profile   *Visibility
+	Age       *Visibility
+	Birthday  *Visibility
+	Zodiac    *Visibility
+	Gender    *Visibility
+	City      *Visibility
+	Interests *Visibility
+	Photos    *Visibility
+}
+
+// Apply применяет изменение настроек. При неизвестном значении настройки не меняются
+func (p *ProfilePrivacy) Apply(patch ProfilePrivacyPatch) error {
+	updated := *p
+	fields := []struct {
+		value  *Visibility
+		target *Visibility
+	}{
+		{patch.Profile, &updated.Profile},
+		{patch.Age, &updated.Age},
+		{patch.Birthday, &updated.Birthday},
+		{patch.Zodiac, &updated.Zodiac},
+		{patch.Gender, &updated.Gender},
+		{patch.City, &updated.City},
+		{patch.Interests, &updated.Interests},
+		{patch.Photos, &updated.Photos},
+	}
+
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		if !field.value.IsValid() {
+			return ErrInvalidVisibility
+		}
+		*field.target = *field.value
+	}
+
+	*p = updated
+	return nil
+}
+
+// ViewFor возвращает копию анкеты, в которой оставлены только поля, видимые зрителю.
+// Настройки видимости видит только владелец. ok равен false, если анкета скрыта от зрителя целиком
+func (p *Profile) ViewFor(relation ViewerRelation) (view *Profile, ok bool) {
+	if relation == ViewerOwner {
+		return p, true
+	}
+
+	privacy := DefaultProfilePrivacy()
+	if p.Privacy != nil {
+		privacy = *p.Privacy
+	}
+
+	if !privacy.Profile.VisibleTo(relation) {
+		return nil, false
+	}
+
+	copied := *p
+	copied.Privacy = nil
+	copied.BirthDate = Date{}
+	if !privacy.Age.VisibleTo(relation) {
+		copied.Age = 0
+	}
+	if !privacy.Birthday.VisibleTo(relation) {
+		copied.Birthday = ""
+	}
+	if !privacy.Zodiac.VisibleTo(relation) {
+		copied.Zodiac = ""
+	}
+	if !privacy.Gender.VisibleTo(relation) {
+		copied.Gender = ""
+	}
+	if !privacy.City.VisibleTo(relation) {
+		copied.City = ""
+	}
+	if !privacy.Interests.VisibleTo(relation) {
+		copied.Interests = nil
+	}
+	if !privacy.Photos.VisibleTo(relation) {
+		copied.Photos = nil
+	}
+
+	return &copied, true
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+)
+
+type RefreshToken struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"` // Храним только хеш токена
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewRefreshToken создает новый refresh токен в рамках цепочки ротаций
+func NewRefreshToken(userID int, familyID, tokenHash string, ttl time.Duration) *RefreshToken {
+	now := time.Now()
+	return &RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired проверяет, истек ли срок действия токена
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsRevoked проверяет, был ли токен отозван
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
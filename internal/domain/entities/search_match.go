@@ -0,0 +1,19 @@
+package entities
+
+// MaxSearchQueryLength - максимальная длина текстового запроса поиска анкет в символах
+const MaxSearchQueryLength = 200
+
+// Поля анкеты, в которых выделяются совпадения с текстовым запросом
+const (
+	SearchFieldName      = "name"
+	SearchFieldCity      = "city"
+	SearchFieldInterests = "interests"
+)
+
+// SearchMatch описывает совпадение анкеты с текстовым запросом поиска
+type SearchMatch struct {
+	Score float64 `json:"score"` // Релевантность: чем больше, тем лучше анкета соответствует запросу
+	// Highlights содержит поля анкеты с совпадениями. Текст экранирован для HTML,
+	// а совпадения выделены тегом <mark>
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// maxUserAgentLength ограничивает длину сохраняемого User-Agent, который присылает клиент
+const maxUserAgentLength = 512
+
+// Session описывает один вход пользователя. ID совпадает с цепочкой refresh токенов и sid в access токенах
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     int        `json:"user_id"`
+	Device     string     `json:"device"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewSession создает сессию для нового входа. Устройство определяется по User-Agent
+func NewSession(id string, userID int, userAgent, ip string, ttl time.Duration) *Session {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	return &Session{
+		ID:         id,
+		UserID:     userID,
+		Device:     DescribeDevice(userAgent),
+		UserAgent:  userAgent,
+		IP:         ip,
+		ExpiresAt:  now.Add(ttl),
+		LastSeenAt: now,
+		CreatedAt:  now,
+	}
+}
+
+// IsActive проверяет, что сессия не завершена и не истекла
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// userAgentBrowsers и userAgentPlatforms перечислены в порядке проверки:
+// например, User-Agent Edge содержит и "Chrome", и "Safari"
+var (
+	userAgentBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"YaBrowser/", "Yandex Browser"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"okhttp/", "OkHttp"},
+	}
+	userAgentPlatforms = []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DescribeDevice возвращает краткое описание устройства по User-Agent, например "Chrome on Windows"
+func DescribeDevice(userAgent string) string {
+	var browser, platform string
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, p := range userAgentPlatforms {
+		if strings.Contains(userAgent, p.token) {
+			platform = p.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+)
+
+// TwoFactor хранит настройки TOTP двухфакторной аутентификации пользователя
+type TwoFactor struct {
+	UserID       int        `json:"user_id"`
+	Secret       string     `json:"-"` // Секрет никогда не отдаем после регистрации
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsEnabled проверяет, подтверждена ли настройка 2FA первым кодом
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
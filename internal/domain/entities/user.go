@@ -7,12 +7,27 @@ import (
 	"time"
 )
 
+// Роли пользователей
+const (
+	RoleAdmin     = "admin"     // Управление пользователями и модерация анкет
+	RoleModerator = "moderator" // Модерация анкет
+)
+
+var (
+	ErrEmailRequired = errors.New("email cannot be empty")
+	ErrInvalidEmail  = errors.New("invalid email format")
+)
+
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Не включаем в JSON
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	PasswordHash    string     `json:"-"` // Не включаем в JSON
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"` // Время удаления аккаунта до окончательной очистки
+	Roles           []string   `json:"roles,omitempty"`
+	SuspendedAt     *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // NewUser создает нового пользователя с валидацией
@@ -36,14 +51,46 @@ func NewUser(email, passwordHash string) (*User, error) {
 // validateEmail проверяет корректность email адреса
 func validateEmail(email string) error {
 	if email == "" {
-		return errors.New("email cannot be empty")
+		return ErrEmailRequired
 	}
 
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(email) {
-		return errors.New("invalid email format")
+		return ErrInvalidEmail
+	}
+
+	return nil
+}
+
+// IsEmailVerified проверяет, подтвердил ли пользователь владение email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasRole проверяет, есть ли у пользователя роль
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSuspended проверяет, заблокирован ли пользователь
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// ChangeEmail меняет email пользователя. Новый адрес требует повторного подтверждения.
+func (u *User) ChangeEmail(email string) error {
+	if err := validateEmail(email); err != nil {
+		return err
 	}
 
+	u.Email = strings.ToLower(email)
+	u.EmailVerifiedAt = nil
+	u.TouchUpdatedAt()
 	return nil
 }
 
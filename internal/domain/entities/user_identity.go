@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+)
+
+// UserIdentity связывает пользователя с учетной записью у внешнего провайдера (OIDC)
+type UserIdentity struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"` // Идентификатор пользователя у провайдера (claim sub)
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OAuthState хранит данные незавершенного входа через провайдера до получения ответа
+type OAuthState struct {
+	StateHash    string    `json:"-"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"-"` // PKCE code_verifier
+	Nonce        string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewOAuthState создает состояние входа через провайдера
+func NewOAuthState(stateHash, provider, codeVerifier, nonce string, ttl time.Duration) *OAuthState {
+	now := time.Now()
+	return &OAuthState{
+		StateHash:    stateHash,
+		Provider:     provider,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+}
+
+// IsExpired проверяет, истек ли срок ожидания ответа провайдера
+func (s *OAuthState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
@@ -0,0 +1,48 @@
+package entities
+
+// ZodiacSign - знак зодиака
+type ZodiacSign string
+
+const (
+	ZodiacAries       ZodiacSign = "aries"
+	ZodiacTaurus      ZodiacSign = "taurus"
+	ZodiacGemini      ZodiacSign = "gemini"
+	ZodiacCancer      ZodiacSign = "cancer"
+	ZodiacLeo         ZodiacSign = "leo"
+	ZodiacVirgo       ZodiacSign = "virgo"
+	ZodiacLibra       ZodiacSign = "libra"
+	ZodiacScorpio     ZodiacSign = "scorpio"
+	ZodiacSagittarius ZodiacSign = "sagittarius"
+	ZodiacCapricorn   ZodiacSign = "capricorn"
+	ZodiacAquarius    ZodiacSign = "aquarius"
+	ZodiacPisces      ZodiacSign = "pisces"
+)
+
+// zodiacStarts содержит для каждого месяца знак, который начинается в этом месяце, и день его начала.
+// До этого дня действует знак, начавшийся в предыдущем месяце
+var zodiacStarts = [12]struct {
+	day  int
+	sign ZodiacSign
+}{
+	{20, ZodiacAquarius},    // январь
+	{19, ZodiacPisces},      // февраль
+	{21, ZodiacAries},       // март
+	{20, ZodiacTaurus},      // апрель
+	{21, ZodiacGemini},      // май
+	{21, ZodiacCancer},      // июнь
+	{23, ZodiacLeo},         // июль
+	{23, ZodiacVirgo},       // август
+	{23, ZodiacLibra},       // сентябрь
+	{23, ZodiacScorpio},     // октябрь
+	{22, ZodiacSagittarius}, // ноябрь
+	{22, ZodiacCapricorn},   // декабрь
+}
+
+// ZodiacSign возвращает знак зодиака для даты рождения
+func (d Date) ZodiacSign() ZodiacSign {
+	month := int(d.Month()) - 1
+	if d.Day() >= zodiacStarts[month].day {
+		return zodiacStarts[month].sign
+	}
+	return zodiacStarts[(month+11)%12].sign
+}
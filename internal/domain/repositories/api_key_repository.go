@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// APIKeyRepository определяет интерфейс для работы с API ключами
+type APIKeyRepository interface {
+	// Create сохраняет новый API ключ
+	Create(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error)
+
+	// GetByHash получает API ключ по хешу
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+
+	// ListByUser возвращает неотозванные ключи пользователя
+	ListByUser(ctx context.Context, userID int) ([]*entities.APIKey, error)
+
+	// Revoke отзывает ключ пользователя и возвращает false, если ключ не найден или уже отозван
+	Revoke(ctx context.Context, id, userID int) (bool, error)
+
+	// TouchLastUsed обновляет время последнего использования ключа
+	TouchLastUsed(ctx context.Context, id int) error
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// AuditLogFilter ограничивает выборку журнала. Нулевые значения не ограничивают выборку
+type AuditLogFilter struct {
+	ActorID int
+	UserID  int
+	Limit   int
+	Offset  int
+}
+
+// AuditLogRepository определяет интерфейс для работы с журналом аудита
+type AuditLogRepository interface {
+	// Create добавляет запись в журнал
+	Create(ctx context.Context, entry *entities.AuditLogEntry) error
+
+	// List возвращает записи журнала, новые первыми
+	List(ctx context.Context, filter AuditLogFilter) ([]*entities.AuditLogEntry, error)
+}
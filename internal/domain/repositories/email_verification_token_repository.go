@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// EmailVerificationTokenRepository определяет интерфейс для работы с токенами подтверждения email
+type EmailVerificationTokenRepository interface {
+	// Create сохраняет новый токен
+	Create(ctx context.Context, token *entities.EmailVerificationToken) (*entities.EmailVerificationToken, error)
+
+	// GetByHash получает токен по хешу
+	GetByHash(ctx context.Context, tokenHash string) (*entities.EmailVerificationToken, error)
+
+	// MarkUsed помечает токен использованным и возвращает false, если он уже был использован
+	MarkUsed(ctx context.Context, id int) (bool, error)
+
+	// InvalidateForUser делает недействительными все неиспользованные токены пользователя
+	InvalidateForUser(ctx context.Context, userID int) error
+}
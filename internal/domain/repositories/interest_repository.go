@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// InterestRepository определяет интерфейс для работы с таксономией интересов
+type InterestRepository interface {
+	// FindBySynonyms находит канонические интересы по нормализованным написаниям.
+	// Ключ результата - написание; неизвестные написания в результат не попадают
+	FindBySynonyms(ctx context.Context, synonyms []string) (map[string]*entities.Interest, error)
+
+	// Create добавляет интерес с названием на языке locale и его написаниями. Если интерес
+	// с таким slug уже есть, название и написания добавляются к нему
+	Create(ctx context.Context, slug, locale, label string, synonyms []string) (*entities.Interest, error)
+
+	// Suggest возвращает интересы, одно из написаний которых начинается с prefix,
+	// от самых популярных, с названиями на языке locale
+	Suggest(ctx context.Context, prefix, locale string, limit int) ([]*entities.Interest, error)
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// LoginAttemptStore хранит счетчики неудачных попыток входа
+type LoginAttemptStore interface {
+	// Get получает счетчик по ключу. Возвращает nil без ошибки, если неудачных попыток не было
+	Get(ctx context.Context, key string) (*entities.LoginAttempts, error)
+
+	// CompareAndSwap атомарно заменяет счетчик next.Key на next, если он не изменился с момента,
+	// когда был прочитан как seen (nil - счетчика не было). Возвращает false, если счетчик успели изменить
+	CompareAndSwap(ctx context.Context, seen *entities.LoginAttempts, next entities.LoginAttempts) (bool, error)
+
+	// Release уменьшает счетчик на попытку, которая была учтена заранее, но оказалась успешной.
+	// Если время последней неудачной попытки все еще равно reservedAt (его не изменили более поздние
+	// попытки), оно заменяется на previousFailureAt
+	Release(ctx context.Context, key string, reservedAt, previousFailureAt time.Time) error
+
+	// Reset сбрасывает счетчик по ключу
+	Reset(ctx context.Context, key string) error
+
+	// DeleteStale удаляет счетчики, последняя неудачная попытка в которых была раньше before
+	DeleteStale(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// OAuthStateRepository определяет интерфейс для хранения незавершенных входов через провайдера
+type OAuthStateRepository interface {
+	// Create сохраняет состояние входа
+	Create(ctx context.Context, state *entities.OAuthState) error
+
+	// Consume получает и удаляет состояние по хешу, чтобы его нельзя было использовать повторно.
+	// Возвращает nil без ошибки, если состояние не найдено
+	Consume(ctx context.Context, stateHash string) (*entities.OAuthState, error)
+
+	// DeleteExpired удаляет состояния, срок которых истек раньше before
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
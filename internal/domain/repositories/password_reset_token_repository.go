@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// PasswordResetTokenRepository определяет интерфейс для работы с токенами сброса пароля
+type PasswordResetTokenRepository interface {
+	// Create сохраняет новый токен
+	Create(ctx context.Context, token *entities.PasswordResetToken) (*entities.PasswordResetToken, error)
+
+	// GetByHash получает токен по хешу
+	GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error)
+
+	// MarkUsed помечает токен использованным и возвращает false, если он уже был использован
+	MarkUsed(ctx context.Context, id int) (bool, error)
+
+	// InvalidateForUser делает недействительными все неиспользованные токены пользователя
+	InvalidateForUser(ctx context.Context, userID int) error
+}
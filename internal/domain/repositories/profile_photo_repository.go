@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// ErrPhotoNotFound возвращается, если фотография не найдена у анкеты
+var ErrPhotoNotFound = errors.New("photo not found")
+
+// ProfilePhotoRepository определяет интерфейс для работы с фотографиями анкет
+type ProfilePhotoRepository interface {
+	// Create сохраняет фотографию в конце списка. Первая фотография анкеты становится основной
+	Create(ctx context.Context, photo *entities.ProfilePhoto) (*entities.ProfilePhoto, error)
+
+	// ListByProfile возвращает фотографии анкеты в порядке показа
+	ListByProfile(ctx context.Context, profileID int) ([]*entities.ProfilePhoto, error)
+
+	// ListByProfiles возвращает фотографии нескольких анкет, сгруппированные по ID анкеты
+	ListByProfiles(ctx context.Context, profileIDs []int) (map[int][]*entities.ProfilePhoto, error)
+
+	// Count возвращает количество фотографий анкеты
+	Count(ctx context.Context, profileID int) (int, error)
+
+	// Reorder задает порядок фотографий. photoIDs должен содержать все фотографии анкеты
+	Reorder(ctx context.Context, profileID int, photoIDs []int) error
+
+	// SetPrimary делает фотографию основной
+	SetPrimary(ctx context.Context, profileID, photoID int) error
+
+	// Detach отвязывает фотографию от анкеты до удаления ее файлов. Если фотография была основной,
+	// основной становится первая из оставшихся
+	Detach(ctx context.Context, profileID, photoID int) (*entities.ProfilePhoto, error)
+
+	// ListDetached возвращает отвязанные фотографии, файлы которых нужно удалить
+	ListDetached(ctx context.Context, limit int) ([]*entities.ProfilePhoto, error)
+
+	// DeleteDetached удаляет запись об отвязанной фотографии
+	DeleteDetached(ctx context.Context, photoID int) error
+}
@@ -2,17 +2,136 @@ package repositories
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 
 	"github.com/Spoloborota/experiment/internal/domain/entities"
 )
 
-// SearchFilters определяет фильтры для поиска профилей
+var (
+	// ErrProfileNotFound возвращается, если профиль не найден
+	ErrProfileNotFound = errors.New("profile not found")
+
+	// ErrProfileVersionMismatch возвращается, если анкета изменилась после чтения клиентом
+	ErrProfileVersionMismatch = errors.New("profile has been modified")
+
+	// ErrInvalidSearchCursor возвращается, если курсор поиска поврежден или выдан для другой сортировки
+	ErrInvalidSearchCursor = errors.New("invalid search cursor")
+)
+
+const (
+	// DefaultSearchLimit - размер страницы поиска по умолчанию
+	DefaultSearchLimit = 10
+	// MaxSearchLimit - наибольший размер страницы поиска; дальше результаты читаются по курсору
+	MaxSearchLimit = 100
+)
+
+// SearchSort определяет порядок результатов поиска. Порядок всегда полный: при равных
+// значениях анкеты упорядочиваются по ID, поэтому страницы не пересекаются
+type SearchSort string
+
+const (
+	SearchSortNewest    SearchSort = "newest"    // Сначала новые анкеты
+	SearchSortName      SearchSort = "name"      // По имени и фамилии без учета регистра
+	SearchSortAge       SearchSort = "age"       // Сначала младшие; анкеты со скрытым возрастом в конце
+	SearchSortRelevance SearchSort = "relevance" // По релевантности текстовому запросу, только вместе с Query
+)
+
+// IsValid проверяет, что порядок известен
+func (s SearchSort) IsValid() bool {
+	switch s {
+	case SearchSortNewest, SearchSortName, SearchSortAge, SearchSortRelevance:
+		return true
+	}
+	return false
+}
+
+// SearchCursor - позиция в результатах поиска: значения ключей сортировки последней анкеты страницы.
+// Следующая страница начинается сразу после этой анкеты, поэтому добавленные и удаленные анкеты
+// не сдвигают результаты между страницами
+type SearchCursor struct {
+	Sort SearchSort `json:"s"`
+	Keys []string   `json:"k"`
+}
+
+// Encode возвращает курсор в виде непрозрачной строки для клиента
+func (c SearchCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSearchCursor разбирает строку, полученную из Encode
+func DecodeSearchCursor(token string) (*SearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidSearchCursor
+	}
+
+	var cursor SearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || !cursor.Sort.IsValid() || len(cursor.Keys) == 0 {
+		return nil, ErrInvalidSearchCursor
+	}
+
+	return &cursor, nil
+}
+
+// InterestsMatch определяет, как анкета должна совпадать с интересами из фильтра
+type InterestsMatch string
+
+const (
+	InterestsMatchAny InterestsMatch = "any" // Хотя бы один из интересов
+	InterestsMatchAll InterestsMatch = "all" // Все интересы
+)
+
+// IsValid проверяет, что способ совпадения известен
+func (m InterestsMatch) IsValid() bool {
+	return m == InterestsMatchAny || m == InterestsMatchAll
+}
+
+// SearchFilters определяет фильтры для поиска профилей. Фильтры объединяются через И,
+// а значения внутри одного фильтра - через ИЛИ
 type SearchFilters struct {
-	Gender    *string
-	City      *string
+	Genders   []string
+	Cities    []string
 	Interests []string
-	Limit     int
-	Offset    int
+	// InterestsMatch - совпадение с любым из Interests (по умолчанию) или со всеми
+	InterestsMatch InterestsMatch
+	Limit          int
+	Offset         int
+
+	// Sort - порядок результатов. Cursor, если задан, заменяет Offset: поиск продолжается
+	// после анкеты, на которой закончилась предыдущая страница
+	Sort   SearchSort
+	Cursor *SearchCursor
+
+	// ExcludeGenders, ExcludeCities и ExcludeInterests исключают анкеты с любым из значений.
+	// Поле, скрытое от зрителя, анкету не исключает
+	ExcludeGenders   []string
+	ExcludeCities    []string
+	ExcludeInterests []string
+
+	// BirthDateFrom и BirthDateTo ограничивают дату рождения включительно
+	BirthDateFrom *entities.Date
+	BirthDateTo   *entities.Date
+
+	// AgeMin и AgeMax ограничивают возраст включительно
+	AgeMin *int
+	AgeMax *int
+
+	// FirstName и LastName - начало имени и фамилии, сравниваются без учета регистра
+	FirstName *string
+	LastName  *string
+
+	// Query - текстовый запрос по имени, городу и интересам с учетом опечаток. Найденные анкеты
+	// упорядочены по релевантности и содержат Match
+	Query *string
+
+	// ViewerID - пользователь, который ищет анкеты (0 - неавторизованный). Анкеты, скрытые от него,
+	// не находятся, а фильтры по скрытым от него полям не совпадают
+	ViewerID int
+	// ViewerFriendIDs - друзья зрителя, которым видны поля с видимостью friends
+	ViewerFriendIDs []int
 }
 
 // ProfileRepository определяет интерфейс для работы с профилями
@@ -20,17 +139,35 @@ type ProfileRepository interface {
 	// Create создает новый профиль
 	Create(ctx context.Context, profile *entities.Profile) (*entities.Profile, error)
 
-	// GetByID получает профиль по ID
+	// GetByID получает профиль по ID. Скрытые анкеты и анкеты удаленных пользователей не возвращаются
 	GetByID(ctx context.Context, id int) (*entities.Profile, error)
 
+	// GetByIDIncludingHidden получает профиль по ID, включая скрытые модератором
+	GetByIDIncludingHidden(ctx context.Context, id int) (*entities.Profile, error)
+
 	// GetByUserID получает профиль по ID пользователя
 	GetByUserID(ctx context.Context, userID int) (*entities.Profile, error)
 
-	// Update обновляет профиль
-	Update(ctx context.Context, profile *entities.Profile) (*entities.Profile, error)
+	// Update обновляет профиль. Если expectedVersion не 0, профиль обновляется только при совпадении
+	// версии, иначе возвращается ErrProfileVersionMismatch
+	Update(ctx context.Context, profile *entities.Profile, expectedVersion int) (*entities.Profile, error)
+
+	// Patch сохраняет только поля анкеты, перечисленные в fields; значения берутся из profile.
+	// expectedVersion проверяется так же, как в Update
+	Patch(ctx context.Context, profile *entities.Profile, fields entities.ProfilePatch, expectedVersion int) (*entities.Profile, error)
+
+	// BumpVersion увеличивает версию анкеты после изменения связанных данных, например фотографий
+	BumpVersion(ctx context.Context, id int) error
+
+	// UpdatePrivacy сохраняет настройки видимости анкеты пользователя
+	UpdatePrivacy(ctx context.Context, userID int, privacy entities.ProfilePrivacy) (*entities.Profile, error)
+
+	// SetHidden скрывает анкету или снова делает ее видимой
+	SetHidden(ctx context.Context, id int, hidden bool) (*entities.Profile, error)
 
-	// Search ищет профили по фильтрам
-	Search(ctx context.Context, filters SearchFilters) ([]*entities.Profile, error)
+	// Search ищет профили по фильтрам и возвращает курсор следующей страницы (nil, если она пуста).
+	// Для курсора другой сортировки возвращается ErrInvalidSearchCursor
+	Search(ctx context.Context, filters SearchFilters) ([]*entities.Profile, *SearchCursor, error)
 
 	// Count возвращает количество профилей по фильтрам
 	Count(ctx context.Context, filters SearchFilters) (int, error)
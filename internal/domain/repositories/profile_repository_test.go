@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor SearchCursor
+	}{
+		{
+			name:   "newest",
+			cursor: SearchCursor{Sort: SearchSortNewest, Keys: []string{"2026-10-17T12:30:45.123456Z", "42"}},
+		},
+		{
+			name:   "name with unicode",
+			cursor: SearchCursor{Sort: SearchSortName, Keys: []string{"анна", "o'brien \"jr\"", "7"}},
+		},
+		{
+			name:   "age",
+			cursor: SearchCursor{Sort: SearchSortAge, Keys: []string{"2147483647", "1"}},
+		},
+		{
+			name:   "relevance",
+			cursor: SearchCursor{Sort: SearchSortRelevance, Keys: []string{"0.75", "2026-10-17T12:30:45Z", "3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeSearchCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeSearchCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(*decoded, tt.cursor) {
+				t.Errorf("DecodeSearchCursor() = %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeSearchCursorRejectsTampered(t *testing.T) {
+	encode := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload))
+	}
+	valid := SearchCursor{Sort: SearchSortNewest, Keys: []string{"2026-10-17T12:30:45Z", "42"}}.Encode()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a cursor!"},
+		{name: "padded base64", token: valid + "=="},
+		{name: "truncated", token: valid[:len(valid)-3]},
+		{name: "not json", token: encode("newest:42")},
+		{name: "unknown sort", token: encode(`{"s":"oldest","k":["42"]}`)},
+		{name: "missing sort", token: encode(`{"k":["42"]}`)},
+		{name: "no keys", token: encode(`{"s":"newest","k":[]}`)},
+		{name: "keys of wrong type", token: encode(`{"s":"newest","k":[42]}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeSearchCursor(tt.token); !errors.Is(err, ErrInvalidSearchCursor) {
+				t.Errorf("DecodeSearchCursor(%q) error = %v, want ErrInvalidSearchCursor", tt.token, err)
+			}
+		})
+	}
+}
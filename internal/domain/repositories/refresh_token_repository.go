@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// RefreshTokenRepository определяет интерфейс для работы с refresh токенами
+type RefreshTokenRepository interface {
+	// Create сохраняет новый refresh токен
+	Create(ctx context.Context, token *entities.RefreshToken) (*entities.RefreshToken, error)
+
+	// GetByHash получает refresh токен по хешу
+	GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+
+	// Revoke отзывает токен и возвращает false, если он уже был отозван ранее
+	Revoke(ctx context.Context, id int) (bool, error)
+
+	// RevokeFamily отзывает все токены цепочки ротаций
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeAllForUser отзывает все refresh токены пользователя
+	RevokeAllForUser(ctx context.Context, userID int) error
+
+	// DeleteExpired удаляет токены, срок действия которых истек раньше before
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
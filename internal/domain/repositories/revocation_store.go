@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationStore хранит сведения об отозванных access токенах
+type RevocationStore interface {
+	// RevokeToken отзывает токен или сессию по идентификатору (jti или sid) до момента expiresAt
+	RevokeToken(ctx context.Context, tokenID string, userID int, expiresAt time.Time) error
+
+	// RevokeUserTokens отзывает все токены пользователя, выпущенные раньше issuedBefore
+	RevokeUserTokens(ctx context.Context, userID int, issuedBefore time.Time) error
+
+	// IsRevoked проверяет, отозван ли хотя бы один из идентификаторов или все токены пользователя
+	IsRevoked(ctx context.Context, tokenIDs []string, userID int, issuedAt time.Time) (bool, error)
+
+	// DeleteExpired удаляет отзывы токенов, срок действия которых истек раньше before
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// SessionRepository определяет интерфейс для работы с сессиями пользователей
+type SessionRepository interface {
+	// Create сохраняет новую сессию
+	Create(ctx context.Context, session *entities.Session) (*entities.Session, error)
+
+	// ListActiveByUser возвращает незавершенные и неистекшие сессии пользователя, последние активные первыми
+	ListActiveByUser(ctx context.Context, userID int) ([]*entities.Session, error)
+
+	// Touch отмечает активность в сессии: обновляет время, адрес клиента и срок действия
+	Touch(ctx context.Context, id, ip string, expiresAt time.Time) error
+
+	// Revoke завершает сессию пользователя и возвращает false, если она не найдена или уже завершена
+	Revoke(ctx context.Context, id string, userID int) (bool, error)
+
+	// RevokeAllForUser завершает все сессии пользователя
+	RevokeAllForUser(ctx context.Context, userID int) error
+
+	// DeleteStale удаляет сессии, истекшие или завершенные раньше before
+	DeleteStale(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// TwoFactorRepository определяет интерфейс для работы с настройками 2FA и кодами восстановления
+type TwoFactorRepository interface {
+	// GetByUserID получает настройки 2FA пользователя.
+	// Возвращает nil без ошибки, если пользователь не настраивал 2FA.
+	GetByUserID(ctx context.Context, userID int) (*entities.TwoFactor, error)
+
+	// SaveSecret сохраняет новый неподтвержденный секрет, заменяя предыдущий
+	SaveSecret(ctx context.Context, userID int, secret string) (*entities.TwoFactor, error)
+
+	// Enable подтверждает 2FA и сохраняет хеши кодов восстановления.
+	// Возвращает false, если 2FA уже была включена.
+	Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) (bool, error)
+
+	// UseStep отмечает использование временного шага TOTP и возвращает false, если шаг уже использован
+	UseStep(ctx context.Context, userID int, step int64) (bool, error)
+
+	// UseRecoveryCode отмечает код восстановления использованным и возвращает false, если кода нет
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+
+	// Disable отключает 2FA и удаляет коды восстановления
+	Disable(ctx context.Context, userID int) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// UserIdentityRepository определяет интерфейс для работы с привязанными внешними учетными записями
+type UserIdentityRepository interface {
+	// Create привязывает внешнюю учетную запись к пользователю
+	Create(ctx context.Context, identity *entities.UserIdentity) (*entities.UserIdentity, error)
+
+	// GetByProviderSubject получает привязку по провайдеру и идентификатору у провайдера.
+	// Возвращает nil без ошибки, если привязки нет
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error)
+
+	// ListByUser возвращает все привязки пользователя
+	ListByUser(ctx context.Context, userID int) ([]*entities.UserIdentity, error)
+}
@@ -2,18 +2,53 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/Spoloborota/experiment/internal/domain/entities"
 )
 
+var (
+	// ErrUserNotFound возвращается, если пользователь не найден
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrEmailAlreadyExists возвращается при нарушении уникальности email
+	ErrEmailAlreadyExists = errors.New("user with this email already exists")
+)
+
 // UserRepository определяет интерфейс для работы с пользователями
 type UserRepository interface {
 	// Create создает нового пользователя
 	Create(ctx context.Context, user *entities.User) (*entities.User, error)
 
-	// GetByID получает пользователя по ID
+	// GetByID получает пользователя по ID. Удаленные пользователи не возвращаются
 	GetByID(ctx context.Context, id int) (*entities.User, error)
 
-	// GetByEmail получает пользователя по email
+	// GetByEmail получает пользователя по email. Удаленные пользователи не возвращаются
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+
+	// Update обновляет учетные данные пользователя.
+	// Возвращает ErrEmailAlreadyExists, если новый email уже занят.
+	Update(ctx context.Context, user *entities.User) (*entities.User, error)
+
+	// MarkEmailVerified отмечает email подтвержденным, если адрес пользователя все еще равен email
+	MarkEmailVerified(ctx context.Context, id int, email string) (bool, error)
+
+	// SoftDelete отмечает пользователя удаленным. Возвращает false, если пользователь уже удален
+	SoftDelete(ctx context.Context, id int) (bool, error)
+
+	// Delete окончательно удаляет пользователя вместе со всеми связанными данными
+	Delete(ctx context.Context, id int) error
+
+	// List возвращает страницу пользователей, упорядоченных по ID
+	List(ctx context.Context, limit, offset int) ([]*entities.User, error)
+
+	// Count возвращает количество пользователей
+	Count(ctx context.Context) (int, error)
+
+	// SetSuspended блокирует или разблокирует пользователя
+	SetSuspended(ctx context.Context, id int, suspended bool) (*entities.User, error)
+
+	// PurgeDeleted окончательно удаляет пользователей, отмеченных удаленными раньше before
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var ErrEmailUnchanged = errors.New("new email must differ from the current one")
+
+type AccountService struct {
+	userRepo            repositories.UserRepository
+	authService         *AuthService
+	deletionGracePeriod time.Duration
+	exporters           []DataExporter
+}
+
+// NewAccountService создает сервис управления аккаунтом.
+// deletionGracePeriod задает, сколько удаленный аккаунт хранится до окончательной очистки; 0 удаляет
+// при первой очистке после истечения выданных access токенов.
+func NewAccountService(
+	userRepo repositories.UserRepository,
+	authService *AuthService,
+	deletionGracePeriod time.Duration,
+	exporters ...DataExporter,
+) *AccountService {
+	return &AccountService{
+		userRepo:            userRepo,
+		authService:         authService,
+		deletionGracePeriod: deletionGracePeriod,
+		exporters:           exporters,
+	}
+}
+
+// ChangePassword меняет пароль после проверки текущего.
+// Все сессии пользователя завершаются, для текущего клиента выпускается новая пара токенов.
+func (s *AccountService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string, client ClientInfo) (*TokenPair, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.checkCurrentPassword(ctx, user, currentPassword, client.IP); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := s.authService.passwords.HashNew(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = passwordHash
+	user.TouchUpdatedAt()
+	if user, err = s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.LogoutAll(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.authService.startSession(ctx, user, client)
+}
+
+// ChangeEmail меняет email после проверки пароля. Новый адрес считается неподтвержденным,
+// письмо для подтверждения отправляется отдельно через EmailVerificationService.
+func (s *AccountService) ChangeEmail(ctx context.Context, userID int, password, newEmail, clientIP string) (*entities.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.checkCurrentPassword(ctx, user, password, clientIP); err != nil {
+		return nil, err
+	}
+
+	previousEmail := user.Email
+	if err := user.ChangeEmail(newEmail); err != nil {
+		return nil, err
+	}
+	if user.Email == previousEmail {
+		return nil, ErrEmailUnchanged
+	}
+
+	return s.userRepo.Update(ctx, user)
+}
+
+// DeleteAccount удаляет аккаунт после проверки пароля и завершает все сессии.
+// Во время срока хранения аккаунт и анкета скрыты, а затем удаляются вместе со связанными данными.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID int, password, clientIP string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authService.checkCurrentPassword(ctx, user, password, clientIP); err != nil {
+		return err
+	}
+
+	if err := s.authService.LogoutAll(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// Аккаунт удаляется окончательно только фоновой очисткой (см. PurgeDeletedAccounts)
+	_, err = s.userRepo.SoftDelete(ctx, user.ID)
+	return err
+}
+
+// DisableTwoFactor отключает 2FA после проверки пароля и кода из приложения или кода восстановления.
+// Неверные пароли и коды учитываются так же, как неудачные попытки входа.
+func (s *AccountService) DisableTwoFactor(ctx context.Context, userID int, password, code, clientIP string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authService.checkCurrentPassword(ctx, user, password, clientIP); err != nil {
+		return err
+	}
+
+	if err := s.authService.checkTwoFactorCode(ctx, user.ID, user.Email, code, clientIP); err != nil {
+		return err
+	}
+
+	return s.authService.twoFactorService.Disable(ctx, user.ID)
+}
+
+// PurgeDeletedAccounts окончательно удаляет аккаунты, срок хранения которых истек.
+// Записи об отзыве токенов удаляются каскадно вместе с пользователем, поэтому аккаунт хранится
+// не меньше срока действия access токена: иначе выданные до удаления токены снова стали бы действительны
+func (s *AccountService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	retention := max(s.deletionGracePeriod, s.authService.accessTokenTTL)
+	return s.userRepo.PurgeDeleted(ctx, time.Now().Add(-retention))
+}
+
+// ExportData собирает все данные, которые хранятся о пользователе
+func (s *AccountService) ExportData(ctx context.Context, userID int) (*AccountExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &AccountExport{
+		ExportedAt: time.Now(),
+		User:       user,
+		Data:       make(map[string]interface{}, len(s.exporters)),
+	}
+
+	for _, exporter := range s.exporters {
+		data, err := exporter.ExportUserData(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			export.Data[exporter.ExportSection()] = data
+		}
+	}
+
+	return export, nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var (
+	ErrCannotSuspendSelf         = errors.New("cannot suspend your own account")
+	ErrCannotImpersonateSelf     = errors.New("cannot impersonate your own account")
+	ErrCannotImpersonateAdmin    = errors.New("cannot impersonate another administrator")
+	ErrImpersonationReasonNeeded = errors.New("reason is required")
+)
+
+type AdminService struct {
+	userRepo         repositories.UserRepository
+	profileRepo      repositories.ProfileRepository
+	photoService     *PhotoService
+	interestService  *InterestService
+	authService      *AuthService
+	auditService     *AuditService
+	impersonationTTL time.Duration
+}
+
+func NewAdminService(
+	userRepo repositories.UserRepository,
+	profileRepo repositories.ProfileRepository,
+	photoService *PhotoService,
+	interestService *InterestService,
+	authService *AuthService,
+	auditService *AuditService,
+	impersonationTTL time.Duration,
+) *AdminService {
+	return &AdminService{
+		userRepo:         userRepo,
+		profileRepo:      profileRepo,
+		photoService:     photoService,
+		interestService:  interestService,
+		authService:      authService,
+		auditService:     auditService,
+		impersonationTTL: impersonationTTL,
+	}
+}
+
+// ListUsers возвращает страницу пользователей и их общее количество
+func (s *AdminService) ListUsers(ctx context.Context, limit, offset int) ([]*entities.User, int, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, err := s.userRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// SuspendUser блокирует пользователя и завершает все его сессии
+func (s *AdminService) SuspendUser(ctx context.Context, adminID, userID int) (*entities.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotSuspendSelf
+	}
+
+	user, err := s.userRepo.SetSuspended(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.LogoutAll(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UnsuspendUser снимает блокировку с пользователя
+func (s *AdminService) UnsuspendUser(ctx context.Context, userID int) (*entities.User, error) {
+	return s.userRepo.SetSuspended(ctx, userID, false)
+}
+
+// UpdateProfile обновляет любую анкету, в том числе скрытую
+func (s *AdminService) UpdateProfile(ctx context.Context, profileID int, firstName, lastName string, birthDate entities.Date, gender, city string, interests []string) (*entities.Profile, error) {
+	profile, err := s.profileRepo.GetByIDIncludingHidden(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := profile.Update(firstName, lastName, birthDate, gender, city, interests); err != nil {
+		return nil, err
+	}
+
+	if profile.Interests, err = s.interestService.Canonicalize(ctx, profile.Interests); err != nil {
+		return nil, err
+	}
+
+	if profile, err = s.profileRepo.Update(ctx, profile, 0); err != nil {
+		return nil, err
+	}
+
+	if err := s.photoService.AttachPhotos(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// SetProfileHidden скрывает анкету из поиска и просмотра или снова делает ее видимой
+func (s *AdminService) SetProfileHidden(ctx context.Context, profileID int, hidden bool) (*entities.Profile, error) {
+	profile, err := s.profileRepo.SetHidden(ctx, profileID, hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.photoService.AttachPhotos(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// Impersonate выдает администратору короткоживущий токен для входа под пользователем.
+// Выдача токена записывается в журнал аудита вместе с указанной причиной
+func (s *AdminService) Impersonate(ctx context.Context, adminID, userID int, reason string, client ClientInfo) (*TokenPair, *entities.User, error) {
+	if adminID == userID {
+		return nil, nil, ErrCannotImpersonateSelf
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil, ErrImpersonationReasonNeeded
+	}
+
+	admin, err := s.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Вход под другим администратором дал бы доступ к его полномочиям от чужого имени
+	if user.HasRole(entities.RoleAdmin) {
+		return nil, nil, ErrCannotImpersonateAdmin
+	}
+
+	tokens, err := s.authService.IssueImpersonationToken(admin, user, s.impersonationTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.auditService.Record(ctx, &entities.AuditLogEntry{
+		ActorID: admin.ID,
+		UserID:  user.ID,
+		Action:  entities.AuditActionImpersonationStart,
+		IP:      client.IP,
+		Details: reason,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, user, nil
+}
+
+// ListAuditLog возвращает страницу журнала действий от имени пользователей
+func (s *AdminService) ListAuditLog(ctx context.Context, filter repositories.AuditLogFilter) ([]*entities.AuditLogEntry, error) {
+	return s.auditService.List(ctx, filter)
+}
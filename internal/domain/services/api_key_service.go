@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+const (
+	apiKeyPrefix      = "sk_"
+	apiKeyPrefixShown = 8 // Сколько символов ключа после apiKeyPrefix показывать в списке
+	maxAPIKeysPerUser = 20
+
+	// apiKeyUsageInterval ограничивает частоту записи времени последнего использования ключа
+	apiKeyUsageInterval = time.Minute
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrTooManyAPIKeys = errors.New("too many api keys")
+)
+
+type APIKeyService struct {
+	apiKeyRepo repositories.APIKeyRepository
+	userRepo   repositories.UserRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repositories.APIKeyRepository, userRepo repositories.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Create создает API ключ и возвращает его значение. Значение показывается только один раз.
+func (s *APIKeyService) Create(ctx context.Context, userID int, name string, scopes []string, expiresAt *time.Time) (*entities.APIKey, string, error) {
+	existing, err := s.apiKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, "", ErrTooManyAPIKeys
+	}
+
+	secret, err := generateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	key, err := entities.NewAPIKey(userID, name, rawKey[:len(apiKeyPrefix)+apiKeyPrefixShown], hashToken(rawKey), uniqueStrings(scopes), expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, err = s.apiKeyRepo.Create(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, rawKey, nil
+}
+
+// List возвращает действующие и истекшие, но не отозванные ключи пользователя
+func (s *APIKeyService) List(ctx context.Context, userID int) ([]*entities.APIKey, error) {
+	return s.apiKeyRepo.ListByUser(ctx, userID)
+}
+
+// Revoke отзывает ключ пользователя
+func (s *APIKeyService) Revoke(ctx context.Context, userID, keyID int) error {
+	revoked, err := s.apiKeyRepo.Revoke(ctx, keyID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate проверяет API ключ и возвращает claims владельца с областями доступа ключа.
+// Роли пользователя ключу не передаются.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*JWTClaims, error) {
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashToken(rawKey))
+	if err != nil || !key.IsUsable() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil || user.IsSuspended() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > apiKeyUsageInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &JWTClaims{
+		UserID:   user.ID,
+		Email:    user.Email,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// uniqueStrings удаляет повторы, сохраняя порядок
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// ExportSection возвращает имя раздела API ключей в экспорте аккаунта
+func (s *APIKeyService) ExportSection() string {
+	return "api_keys"
+}
+
+// ExportUserData возвращает действующие API ключи для экспорта аккаунта. Хеши ключей не выгружаются
+func (s *APIKeyService) ExportUserData(ctx context.Context, userID int) (interface{}, error) {
+	return s.apiKeyRepo.ListByUser(ctx, userID)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+// AuditService ведет журнал действий администраторов от имени пользователей
+type AuditService struct {
+	auditRepo repositories.AuditLogRepository
+}
+
+func NewAuditService(auditRepo repositories.AuditLogRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record добавляет запись в журнал
+func (s *AuditService) Record(ctx context.Context, entry *entities.AuditLogEntry) error {
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// List возвращает страницу журнала, новые записи первыми
+func (s *AuditService) List(ctx context.Context, filter repositories.AuditLogFilter) ([]*entities.AuditLogEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.auditRepo.List(ctx, filter)
+}
@@ -2,33 +2,149 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/Spoloborota/experiment/internal/domain/entities"
 	"github.com/Spoloborota/experiment/internal/domain/repositories"
 )
 
+var (
+	ErrInvalidCredentials        = errors.New("invalid credentials")
+	ErrIncorrectPassword         = errors.New("current password is incorrect")
+	ErrAccountSuspended          = errors.New("account is suspended")
+	ErrInvalidRefreshToken       = errors.New("invalid refresh token")
+	ErrRefreshTokenReused        = errors.New("refresh token reuse detected")
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+	ErrSessionNotFound           = errors.New("session not found")
+)
+
+const (
+	// tokenPurposeTwoFactor помечает промежуточный токен, выданный после проверки пароля
+	// пользователю с включенной 2FA. Такой токен нельзя использовать как access токен.
+	tokenPurposeTwoFactor = "2fa"
+	twoFactorChallengeTTL = 5 * time.Minute
+)
+
 type AuthService struct {
-	userRepo       repositories.UserRepository
-	jwtSecret      string
-	jwtExpiryHours int
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	sessionRepo      repositories.SessionRepository
+	revocationStore  repositories.RevocationStore
+	keys             KeyProvider
+	passwords        *PasswordManager
+	twoFactorService *TwoFactorService
+	loginThrottler   *LoginThrottler
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
 }
 
 type JWTClaims struct {
+	UserID    int      `json:"user_id"`
+	Email     string   `json:"email"`
+	SessionID string   `json:"sid,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Purpose   string   `json:"purpose,omitempty"`
+
+	// Actor заполняется в токене, выданном администратору для входа под пользователем (RFC 8693)
+	Actor *ActorClaim `json:"act,omitempty"`
+	jwt.RegisteredClaims
+
+	// APIKeyID и Scopes заполняются при аутентификации по API ключу и не попадают в JWT
+	APIKeyID int      `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// HasScope проверяет область доступа. Вход по паролю дает доступ ко всем областям,
+// API ключ - только к выданным при его создании.
+func (c *JWTClaims) HasScope(scope string) bool {
+	if c.APIKeyID == 0 {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ActorClaim описывает администратора, который действует от имени пользователя
+type ActorClaim struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
-	jwt.RegisteredClaims
 }
 
-func NewAuthService(userRepo repositories.UserRepository, jwtSecret string, jwtExpiryHours int) *AuthService {
+// IsImpersonated проверяет, выдан ли токен администратору для входа под пользователем
+func (c *JWTClaims) IsImpersonated() bool {
+	return c.Actor != nil
+}
+
+// HasRole проверяет, есть ли роль в токене
+func (c *JWTClaims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientInfo описывает клиента, с которого выполняется вход. IP используется для ограничения попыток,
+// IP и User-Agent сохраняются в сессии
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginResult содержит результат входа: пару токенов или, если включена 2FA, вызов второго фактора
+type LoginResult struct {
+	User      *entities.User
+	Tokens    *TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+// TwoFactorChallenge содержит токен, который обменивается на пару токенов вместе с кодом 2FA
+type TwoFactorChallenge struct {
+	Token     string
+	ExpiresIn time.Duration
+}
+
+// TokenPair содержит короткоживущий access токен и refresh токен для его обновления
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+func NewAuthService(
+	userRepo repositories.UserRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	sessionRepo repositories.SessionRepository,
+	revocationStore repositories.RevocationStore,
+	keys KeyProvider,
+	passwords *PasswordManager,
+	twoFactorService *TwoFactorService,
+	loginThrottler *LoginThrottler,
+	accessTokenTTL, refreshTokenTTL time.Duration,
+) *AuthService {
 	return &AuthService{
-		userRepo:       userRepo,
-		jwtSecret:      jwtSecret,
-		jwtExpiryHours: jwtExpiryHours,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		revocationStore:  revocationStore,
+		keys:             keys,
+		passwords:        passwords,
+		twoFactorService: twoFactorService,
+		loginThrottler:   loginThrottler,
+		accessTokenTTL:   accessTokenTTL,
+		refreshTokenTTL:  refreshTokenTTL,
 	}
 }
 
@@ -37,11 +153,11 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*en
 	// Проверяем, существует ли пользователь
 	existingUser, err := s.userRepo.GetByEmail(ctx, email)
 	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, repositories.ErrEmailAlreadyExists
 	}
 
 	// Хешируем пароль
-	passwordHash, err := s.hashPassword(password)
+	passwordHash, err := s.passwords.HashNew(password)
 	if err != nil {
 		return nil, err
 	}
@@ -56,33 +172,218 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*en
 	return s.userRepo.Create(ctx, user)
 }
 
-// Login авторизует пользователя и возвращает JWT токен
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, *entities.User, error) {
+// Login авторизует пользователя. Если у пользователя включена 2FA,
+// вместо токенов возвращается вызов, который нужно подтвердить кодом через CompleteTwoFactorLogin.
+// При слишком частых неудачных попытках с аккаунтом или адреса клиента возвращает *LoginThrottledError.
+func (s *AuthService) Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error) {
+	// Отклоняем попытку до проверки пароля, чтобы не тратить время на хеширование.
+	// Разрешенная попытка сразу учитывается как неудачная, пока пароль не окажется верным
+	reservation, err := s.loginThrottler.Reserve(ctx, email, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
 	// Получаем пользователя
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
+		return nil, s.loginFailed(ctx, email, client.IP, ErrInvalidCredentials)
 	}
 
 	// Проверяем пароль
-	if !s.checkPassword(password, user.PasswordHash) {
-		return "", nil, errors.New("invalid credentials")
+	ok, needsRehash := s.passwords.Verify(password, user.PasswordHash)
+	if !ok {
+		return nil, s.loginFailed(ctx, email, client.IP, ErrInvalidCredentials)
+	}
+	if err := s.loginThrottler.Release(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	// Хеш, полученный устаревшим алгоритмом или параметрами, пересчитываем, пока известен пароль
+	if needsRehash {
+		if user, err = s.rehashPassword(ctx, user, password); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.IsSuspended() {
+		return nil, ErrAccountSuspended
+	}
+
+	challenge, err := s.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &LoginResult{User: user, Challenge: challenge}, nil
+	}
+
+	if err := s.loginThrottler.RecordSuccess(ctx, email); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// LoginWithIdentity авторизует пользователя, личность которого подтвердил внешний провайдер.
+// Как и при входе по паролю, при включенной 2FA возвращается вызов второго фактора.
+func (s *AuthService) LoginWithIdentity(ctx context.Context, user *entities.User, client ClientInfo) (*LoginResult, error) {
+	if user.IsSuspended() {
+		return nil, ErrAccountSuspended
+	}
+
+	challenge, err := s.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &LoginResult{User: user, Challenge: challenge}, nil
+	}
+
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// CompleteTwoFactorLogin обменивает вызов 2FA и код из приложения (или код восстановления) на пару токенов
+// Неверные коды учитываются так же, как неверные пароли.
+func (s *AuthService) CompleteTwoFactorLogin(ctx context.Context, challengeToken, code string, client ClientInfo) (*TokenPair, *entities.User, error) {
+	claims, err := s.parseToken(challengeToken)
+	if err != nil || claims.Purpose != tokenPurposeTwoFactor {
+		return nil, nil, ErrInvalidTwoFactorChallenge
 	}
 
-	// Генерируем JWT токен
-	token, err := s.generateJWT(user)
+	revoked, err := s.IsTokenRevoked(ctx, claims)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
+	}
+	if revoked {
+		return nil, nil, ErrInvalidTwoFactorChallenge
 	}
 
-	return token, user, nil
+	if err := s.checkTwoFactorCode(ctx, claims.UserID, claims.Email, code, client.IP); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.loginThrottler.RecordSuccess(ctx, claims.Email); err != nil {
+		return nil, nil, err
+	}
+
+	// Вызов одноразовый: после успешного входа его нельзя использовать повторно
+	if err := s.revocationStore.RevokeToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidTwoFactorChallenge
+	}
+
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, user, nil
+}
+
+// checkTwoFactorCode проверяет код 2FA с тем же ограничением попыток, что и у пароля
+func (s *AuthService) checkTwoFactorCode(ctx context.Context, userID int, email, code, clientIP string) error {
+	reservation, err := s.loginThrottler.Reserve(ctx, email, clientIP)
+	if err != nil {
+		return err
+	}
+
+	if err := s.twoFactorService.VerifyCode(ctx, userID, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			return s.loginFailed(ctx, email, clientIP, err)
+		}
+		// Код не проверен из-за внутренней ошибки, поэтому попытка не считается неудачной
+		if releaseErr := s.loginThrottler.Release(ctx, reservation); releaseErr != nil {
+			return releaseErr
+		}
+		return err
+	}
+
+	return s.loginThrottler.Release(ctx, reservation)
+}
+
+// Refresh обменивает refresh токен на новую пару токенов.
+// Использованный токен отзывается; повторное предъявление уже отозванного
+// токена считается утечкой, и вся цепочка ротаций отзывается вместе с сессией.
+// Время последней активности и адрес клиента в сессии обновляются при каждом обмене.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error) {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.IsRevoked() {
+		if err := s.revokeFamily(ctx, stored.UserID, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if stored.IsExpired() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Отзываем текущий токен; если его успели отозвать параллельно,
+	// значит токен был использован дважды
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		if err := s.revokeFamily(ctx, stored.UserID, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	tokens, err := s.issueTokens(ctx, user, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Touch(ctx, stored.FamilyID, client.IP, time.Now().Add(s.refreshTokenTTL)); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
 }
 
 // ValidateToken валидирует JWT токен и возвращает информацию о пользователе
 func (s *AuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
-	})
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Промежуточные токены (например, вызов 2FA) не дают доступа к API
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// parseToken проверяет подпись и срок действия JWT токена
+func (s *AuthService) parseToken(tokenString string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, s.verificationKey)
 
 	if err != nil {
 		return nil, err
@@ -95,42 +396,343 @@ func (s *AuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// verificationKey выбирает ключ проверки по заголовку kid.
+// Алгоритм токена должен совпадать с алгоритмом ключа, иначе возможна подмена алгоритма.
+func (s *AuthService) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := s.keys.VerificationKey(kid)
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	return key.Key, nil
+}
+
+// IsTokenRevoked проверяет, не был ли токен отозван через выход из системы
+func (s *AuthService) IsTokenRevoked(ctx context.Context, claims *JWTClaims) (bool, error) {
+	tokenIDs := []string{claims.ID}
+	if claims.SessionID != "" {
+		tokenIDs = append(tokenIDs, claims.SessionID)
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	return s.revocationStore.IsRevoked(ctx, tokenIDs, claims.UserID, issuedAt)
+}
+
+// Logout завершает текущую сессию: отзывает access токен и все refresh токены этого входа
+func (s *AuthService) Logout(ctx context.Context, claims *JWTClaims) error {
+	expiresAt := time.Now().Add(s.accessTokenTTL)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	if err := s.revocationStore.RevokeToken(ctx, claims.ID, claims.UserID, expiresAt); err != nil {
+		return err
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	if _, err := s.sessionRepo.Revoke(ctx, claims.SessionID, claims.UserID); err != nil {
+		return err
+	}
+
+	return s.revokeSessionTokens(ctx, claims.UserID, claims.SessionID)
+}
+
+// ListSessions возвращает действующие сессии пользователя
+func (s *AuthService) ListSessions(ctx context.Context, userID int) ([]*entities.Session, error) {
+	return s.sessionRepo.ListActiveByUser(ctx, userID)
+}
+
+// RevokeSession завершает одну из сессий пользователя, например вход на потерянном устройстве
+func (s *AuthService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	// Сессия ищется с учетом владельца, поэтому чужую сессию завершить нельзя
+	revoked, err := s.sessionRepo.Revoke(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	return s.revokeSessionTokens(ctx, userID, sessionID)
+}
+
+// ExportSection возвращает имя раздела сессий в экспорте аккаунта
+func (s *AuthService) ExportSection() string {
+	return "sessions"
+}
+
+// ExportUserData возвращает действующие сессии для экспорта аккаунта
+func (s *AuthService) ExportUserData(ctx context.Context, userID int) (interface{}, error) {
+	return s.sessionRepo.ListActiveByUser(ctx, userID)
+}
+
+// PurgeStaleSessions удаляет истекшие и завершенные сессии
+func (s *AuthService) PurgeStaleSessions(ctx context.Context) error {
+	return s.sessionRepo.DeleteStale(ctx, time.Now())
+}
+
+// PurgeExpiredTokens удаляет истекшие refresh токены и отзывы истекших access токенов
+func (s *AuthService) PurgeExpiredTokens(ctx context.Context) error {
+	now := time.Now()
+	if err := s.revocationStore.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.DeleteExpired(ctx, now)
+}
+
+// LogoutAll завершает все сессии пользователя на всех устройствах
+func (s *AuthService) LogoutAll(ctx context.Context, userID int) error {
+	// iat в токене хранится с точностью до секунды, поэтому и границу отзыва округляем до секунды
+	if err := s.revocationStore.RevokeUserTokens(ctx, userID, time.Now().Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeAllForUser(ctx, userID)
+}
+
 // GetUserByID получает пользователя по ID
 func (s *AuthService) GetUserByID(ctx context.Context, userID int) (*entities.User, error) {
 	return s.userRepo.GetByID(ctx, userID)
 }
 
-// hashPassword хеширует пароль с помощью bcrypt
-func (s *AuthService) hashPassword(password string) (string, error) {
-	if len(password) < 6 {
-		return "", errors.New("password must be at least 6 characters long")
+// checkCurrentPassword проверяет текущий пароль при изменении учетных данных.
+// Неверные пароли учитываются так же, как неудачные попытки входа.
+func (s *AuthService) checkCurrentPassword(ctx context.Context, user *entities.User, password, clientIP string) error {
+	reservation, err := s.loginThrottler.Reserve(ctx, user.Email, clientIP)
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := s.passwords.Verify(password, user.PasswordHash); !ok {
+		return s.loginFailed(ctx, user.Email, clientIP, ErrIncorrectPassword)
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return s.loginThrottler.Release(ctx, reservation)
+}
+
+// rehashPassword сохраняет хеш пароля, пересчитанный текущим алгоритмом
+func (s *AuthService) rehashPassword(ctx context.Context, user *entities.User, password string) (*entities.User, error) {
+	passwordHash, err := s.passwords.Rehash(password)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	user.PasswordHash = passwordHash
+	return s.userRepo.Update(ctx, user)
+}
+
+// loginFailed завершает неудачную попытку входа. Если следующую попытку придется отложить,
+// возвращает *LoginThrottledError вместо исходной ошибки.
+func (s *AuthService) loginFailed(ctx context.Context, email, clientIP string, cause error) error {
+	if err := s.loginThrottler.RecordFailure(ctx, email, clientIP); err != nil {
+		return err
+	}
+	return cause
+}
+
+// startSession начинает новую сессию: каждый вход открывает свою цепочку ротаций refresh токенов
+func (s *AuthService) startSession(ctx context.Context, user *entities.User, client ClientInfo) (*TokenPair, error) {
+	if user.IsSuspended() {
+		return nil, ErrAccountSuspended
+	}
+
+	familyID, err := generateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sessionRepo.Create(ctx, entities.NewSession(familyID, user.ID, client.UserAgent, client.IP, s.refreshTokenTTL)); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, familyID)
+}
+
+// revokeSessionTokens отзывает access токены сессии по sid и цепочку ее refresh токенов
+func (s *AuthService) revokeSessionTokens(ctx context.Context, userID int, sessionID string) error {
+	// Отзываем сессию целиком, чтобы ранее выпущенные в ней access токены тоже перестали действовать
+	if err := s.revocationStore.RevokeToken(ctx, sessionID, userID, time.Now().Add(s.accessTokenTTL)); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, sessionID)
+}
+
+// revokeFamily отзывает цепочку refresh токенов после обнаружения повторного использования
+// и завершает связанную с ней сессию
+func (s *AuthService) revokeFamily(ctx context.Context, userID int, familyID string) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	_, err := s.sessionRepo.Revoke(ctx, familyID, userID)
+	return err
+}
+
+// twoFactorChallenge выпускает вызов 2FA, если она включена у пользователя, иначе возвращает nil
+func (s *AuthService) twoFactorChallenge(ctx context.Context, user *entities.User) (*TwoFactorChallenge, error) {
+	enabled, err := s.twoFactorService.IsEnabled(ctx, user.ID)
+	if err != nil || !enabled {
+		return nil, err
 	}
 
-	return string(hash), nil
+	return s.generateTwoFactorChallenge(user)
 }
 
-// checkPassword проверяет пароль против хеша
-func (s *AuthService) checkPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// generateTwoFactorChallenge выпускает короткоживущий токен вызова 2FA
+func (s *AuthService) generateTwoFactorChallenge(user *entities.User) (*TwoFactorChallenge, error) {
+	tokenID, err := generateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &JWTClaims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Purpose: tokenPurposeTwoFactor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(twoFactorChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+		},
+	}
+
+	token, err := s.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorChallenge{
+		Token:     token,
+		ExpiresIn: twoFactorChallengeTTL,
+	}, nil
+}
+
+// issueTokens выпускает access токен и новый refresh токен в рамках цепочки familyID
+func (s *AuthService) issueTokens(ctx context.Context, user *entities.User, familyID string) (*TokenPair, error) {
+	if user.IsSuspended() {
+		return nil, ErrAccountSuspended
+	}
+
+	accessToken, err := s.generateJWT(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.refreshTokenRepo.Create(ctx, entities.NewRefreshToken(user.ID, familyID, hashToken(refreshToken), s.refreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.accessTokenTTL,
+	}, nil
+}
+
+// IssueImpersonationToken выпускает администратору actor короткоживущий access токен пользователя target.
+// Refresh токен и сессия не создаются: по истечении токена нужно запросить новый
+func (s *AuthService) IssueImpersonationToken(actor, target *entities.User, ttl time.Duration) (*TokenPair, error) {
+	if target.IsSuspended() {
+		return nil, ErrAccountSuspended
+	}
+
+	tokenID, err := generateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &JWTClaims{
+		UserID: target.ID,
+		Email:  target.Email,
+		Roles:  target.Roles,
+		Actor: &ActorClaim{
+			UserID: actor.ID,
+			Email:  actor.Email,
+		},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+		},
+	}
+
+	accessToken, err := s.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken: accessToken,
+		ExpiresIn:   ttl,
+	}, nil
 }
 
 // generateJWT генерирует JWT токен для пользователя
-func (s *AuthService) generateJWT(user *entities.User) (string, error) {
+func (s *AuthService) generateJWT(user *entities.User, sessionID string) (string, error) {
+	tokenID, err := generateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
 	claims := &JWTClaims{
-		UserID: user.ID,
-		Email:  user.Email,
+		UserID:    user.ID,
+		Email:     user.Email,
+		SessionID: sessionID,
+		Roles:     user.Roles,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(s.jwtExpiryHours) * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return s.signToken(claims)
+}
+
+// signToken подписывает токен текущим ключом и указывает его kid в заголовке
+func (s *AuthService) signToken(claims *JWTClaims) (string, error) {
+	signingKey := s.keys.SigningKey()
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	token.Header["kid"] = signingKey.ID
+	return token.SignedString(signingKey.Key)
+}
+
+// generateRandomToken генерирует криптографически случайную строку из size байт
+func generateRandomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken возвращает SHA-256 хеш непрозрачного токена для хранения в базе
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// DataExporter выгружает данные пользователя, которые хранит один из сервисов.
+// Новые данные попадают в экспорт аккаунта, если их сервис реализует этот интерфейс
+// и передан в NewAccountService.
+type DataExporter interface {
+	// ExportSection возвращает имя раздела в архиве
+	ExportSection() string
+
+	// ExportUserData возвращает данные пользователя или nil, если данных нет
+	ExportUserData(ctx context.Context, userID int) (interface{}, error)
+}
+
+// AccountExport содержит все данные, которые хранятся о пользователе
+type AccountExport struct {
+	ExportedAt time.Time              `json:"exported_at"`
+	User       *entities.User         `json:"user"`
+	Data       map[string]interface{} `json:"data"`
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+)
+
+type EmailVerificationService struct {
+	userRepo  repositories.UserRepository
+	tokenRepo repositories.EmailVerificationTokenRepository
+	mailer    MailSender
+	tokenTTL  time.Duration
+	verifyURL string
+}
+
+func NewEmailVerificationService(
+	userRepo repositories.UserRepository,
+	tokenRepo repositories.EmailVerificationTokenRepository,
+	mailer MailSender,
+	tokenTTL time.Duration,
+	verifyURL string,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		tokenTTL:  tokenTTL,
+		verifyURL: verifyURL,
+	}
+}
+
+// SendVerification отправляет письмо со ссылкой для подтверждения текущего email пользователя
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *entities.User) error {
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	// Действует только последняя отправленная ссылка
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	verificationToken := entities.NewEmailVerificationToken(user.ID, user.Email, hashToken(token), s.tokenTTL)
+	if _, err := s.tokenRepo.Create(ctx, verificationToken); err != nil {
+		return err
+	}
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, MailMessage{
+		To:      user.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf("Чтобы подтвердить адрес электронной почты, перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %d ч.\n", link, int(s.tokenTTL.Hours())),
+	})
+}
+
+// ResendVerification повторно отправляет письмо для подтверждения email
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, userID int) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+// Verify подтверждает email по токену из письма
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) error {
+	verificationToken, err := s.tokenRepo.GetByHash(ctx, hashToken(token))
+	if err != nil || !verificationToken.IsUsable() {
+		return ErrInvalidVerificationToken
+	}
+
+	used, err := s.tokenRepo.MarkUsed(ctx, verificationToken.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidVerificationToken
+	}
+
+	// Если email успели сменить после отправки письма, токен уже не подтверждает текущий адрес
+	verified, err := s.userRepo.MarkEmailVerified(ctx, verificationToken.UserID, verificationToken.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrInvalidVerificationToken
+	}
+
+	return nil
+}
+
+// IsVerified проверяет, подтвердил ли пользователь свой email
+func (s *EmailVerificationService) IsVerified(ctx context.Context, userID int) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.IsEmailVerified(), nil
+}
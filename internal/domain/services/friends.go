@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+)
+
+// FriendProvider отвечает на вопросы о дружбе пользователей. Используется настройками
+// видимости анкет: поля с видимостью friends видны только друзьям владельца
+type FriendProvider interface {
+	// AreFriends проверяет, дружат ли пользователи
+	AreFriends(ctx context.Context, userID, otherUserID int) (bool, error)
+
+	// FriendIDs возвращает ID друзей пользователя
+	FriendIDs(ctx context.Context, userID int) ([]int, error)
+}
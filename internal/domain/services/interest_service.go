@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+const (
+	defaultInterestSuggestions = 10
+	maxInterestSuggestions     = 50
+	defaultInterestLocale      = "en"
+)
+
+// InterestService сопоставляет интересы, введенные пользователями, с таксономией интересов
+type InterestService struct {
+	interestRepo repositories.InterestRepository
+}
+
+func NewInterestService(interestRepo repositories.InterestRepository) *InterestService {
+	return &InterestService{
+		interestRepo: interestRepo,
+	}
+}
+
+// Canonicalize возвращает slug канонических интересов для названий и синонимов в порядке
+// ввода без повторов. Неизвестные названия добавляются в таксономию как новые интересы
+func (s *InterestService) Canonicalize(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return []string{}, nil
+	}
+
+	normalized := normalizeInterests(names)
+	known, err := s.interestRepo.FindBySynonyms(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		interest, ok := known[normalized[i]]
+		if !ok {
+			label := strings.Join(strings.Fields(name), " ")
+			slug := entities.InterestSlug(normalized[i])
+			interest, err = s.interestRepo.Create(ctx, slug, entities.InterestLocale(label), label, []string{normalized[i]})
+			if err != nil {
+				return nil, err
+			}
+			known[normalized[i]] = interest
+		}
+
+		if !seen[interest.Slug] {
+			slugs = append(slugs, interest.Slug)
+			seen[interest.Slug] = true
+		}
+	}
+
+	return slugs, nil
+}
+
+// Lookup возвращает slug интересов для фильтра поиска. В отличие от Canonicalize неизвестные
+// названия в таксономию не добавляются и остаются в нормализованном виде
+func (s *InterestService) Lookup(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	normalized := normalizeInterests(names)
+	known, err := s.interestRepo.FindBySynonyms(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, len(normalized))
+	for i, name := range normalized {
+		slugs[i] = name
+		if interest, ok := known[name]; ok {
+			slugs[i] = interest.Slug
+		}
+	}
+
+	return slugs, nil
+}
+
+// Suggest возвращает до limit интересов, начинающихся с prefix, с названиями на языке locale
+func (s *InterestService) Suggest(ctx context.Context, prefix, locale string, limit int) ([]*entities.Interest, error) {
+	if limit <= 0 {
+		limit = defaultInterestSuggestions
+	}
+	if limit > maxInterestSuggestions {
+		limit = maxInterestSuggestions
+	}
+	if locale == "" {
+		locale = defaultInterestLocale
+	}
+
+	return s.interestRepo.Suggest(ctx, entities.NormalizeInterest(prefix), strings.ToLower(locale), limit)
+}
+
+// normalizeInterests приводит названия интересов к виду, в котором хранятся синонимы
+func normalizeInterests(names []string) []string {
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		normalized[i] = entities.NormalizeInterest(name)
+	}
+	return normalized
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+const (
+	ThrottleScopeAccount = "account"
+	ThrottleScopeIP      = "ip"
+)
+
+// maxReserveRetries ограничивает число повторов учета попытки, когда счетчик одновременно меняют другие попытки
+const maxReserveRetries = 10
+
+// LoginThrottlePolicy задает ограничения на неудачные попытки входа
+type LoginThrottlePolicy struct {
+	FreeAttempts            int           // Число неудачных попыток без задержки
+	BaseDelay               time.Duration // Задержка после первой попытки сверх FreeAttempts, далее удваивается
+	MaxDelay                time.Duration
+	AccountLockoutThreshold int           // Число неудачных попыток на аккаунт до блокировки
+	IPLockoutThreshold      int           // Число неудачных попыток с одного IP до блокировки
+	LockoutDuration         time.Duration // Длительность блокировки и окно, в котором считаются попытки
+}
+
+// LoginThrottledError возвращается, когда попытка входа отклонена из-за слишком частых неудач
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Scope      string // account или ip
+	Lockout    bool   // true для блокировки, false для прогрессивной задержки
+	Triggered  bool   // Блокировка или задержка наступила в результате этой попытки
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "too many login attempts"
+}
+
+type LoginThrottler struct {
+	store     repositories.LoginAttemptStore
+	policy    LoginThrottlePolicy
+	namespace string // Префикс ключей счетчиков, отделяющий их от счетчиков других действий
+}
+
+// throttleKey описывает один из счетчиков, по которым ограничиваются попытки входа
+type throttleKey struct {
+	key       string
+	scope     string
+	threshold int
+}
+
+// LoginReservation описывает попытку, заранее учтенную Reserve, и нужна, чтобы вернуть ее через Release
+type LoginReservation struct {
+	keys []reservedKey
+}
+
+// reservedKey хранит состояние счетчика до учета попытки
+type reservedKey struct {
+	key               string
+	reservedAt        time.Time // Время неудачной попытки, записанное при учете
+	previousFailureAt time.Time // Время предыдущей неудачной попытки, которое восстанавливает Release
+}
+
+func NewLoginThrottler(store repositories.LoginAttemptStore, policy LoginThrottlePolicy) *LoginThrottler {
+	return &LoginThrottler{
+		store:  store,
+		policy: policy,
+	}
+}
+
+// WithNamespace возвращает ограничитель других действий с теми же ключами аккаунта и IP, счетчики которого
+// хранятся в том же хранилище под префиксом namespace и не влияют на вход. Окно наблюдения policy не должно
+// превышать окно этого ограничителя: устаревшие счетчики всего хранилища удаляет его PurgeStale
+func (t *LoginThrottler) WithNamespace(namespace string, policy LoginThrottlePolicy) *LoginThrottler {
+	return &LoginThrottler{
+		store:     t.store,
+		policy:    policy,
+		namespace: namespace + ":",
+	}
+}
+
+// Reserve проверяет, разрешена ли сейчас попытка входа для email с адреса ip, и, если разрешена,
+// заранее учитывает ее как неудачную. Проверка и учет выполняются атомарно для каждого счетчика,
+// поэтому одновременные попытки не проходят проверку разом: каждая видит попытки, учтенные до нее.
+// Вызывается до проверки пароля, чтобы отклоненные попытки не тратили время на хеширование пароля.
+// После проверки нужно вызвать RecordFailure, если попытка неудачна, или Release, если удачна
+func (t *LoginThrottler) Reserve(ctx context.Context, email, ip string) (*LoginReservation, error) {
+	reservation := &LoginReservation{}
+	for _, k := range t.keys(email, ip) {
+		reserved, err := t.reserve(ctx, k)
+		if err != nil {
+			// Попытка отклонена по одному из счетчиков, поэтому по остальным ее не учитываем
+			if releaseErr := t.Release(ctx, reservation); releaseErr != nil {
+				return nil, releaseErr
+			}
+			return nil, err
+		}
+		reservation.keys = append(reservation.keys, reserved)
+	}
+
+	return reservation, nil
+}
+
+// RecordFailure завершает неудачную попытку входа, уже учтенную Reserve.
+// Возвращает *LoginThrottledError, если следующую попытку придется отложить.
+func (t *LoginThrottler) RecordFailure(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var throttled *LoginThrottledError
+
+	for _, k := range t.keys(email, ip) {
+		attempts, err := t.store.Get(ctx, k.key)
+		if err != nil {
+			return err
+		}
+		if attempts == nil {
+			continue
+		}
+
+		until, lockout := t.blockedUntil(attempts.Failures, attempts.LastFailureAt, k.threshold)
+		if retryAfter := until.Sub(now); retryAfter > 0 && (throttled == nil || retryAfter > throttled.RetryAfter) {
+			throttled = &LoginThrottledError{RetryAfter: retryAfter, Scope: k.scope, Lockout: lockout, Triggered: true}
+		}
+	}
+
+	if throttled != nil {
+		return throttled
+	}
+	return nil
+}
+
+// Release возвращает попытку, учтенную Reserve, если она оказалась удачной. Вместе со счетчиком
+// восстанавливается время предыдущей неудачной попытки, чтобы удачная попытка не продлевала задержку
+func (t *LoginThrottler) Release(ctx context.Context, reservation *LoginReservation) error {
+	for _, k := range reservation.keys {
+		if err := t.store.Release(ctx, k.key, k.reservedAt, k.previousFailureAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordSuccess сбрасывает счетчик аккаунта после успешного входа.
+// Счетчик IP не сбрасывается, чтобы успешный вход в свой аккаунт не обнулял попытки подбора чужих.
+func (t *LoginThrottler) RecordSuccess(ctx context.Context, email string) error {
+	return t.store.Reset(ctx, t.namespace+accountThrottleKey(email))
+}
+
+// PurgeStale удаляет счетчики, которые уже не влияют на попытки входа
+func (t *LoginThrottler) PurgeStale(ctx context.Context) error {
+	return t.store.DeleteStale(ctx, time.Now().Add(-t.policy.LockoutDuration))
+}
+
+// reserve учитывает попытку в счетчике k, если он ее не блокирует. Счетчик заменяется, только
+// если его не изменила одновременная попытка; иначе решение принимается заново по новому значению
+func (t *LoginThrottler) reserve(ctx context.Context, k throttleKey) (reservedKey, error) {
+	for i := 0; i < maxReserveRetries; i++ {
+		// Хранилище может округлять время до микросекунд, а Release сравнивает его с записанным точно
+		now := time.Now().Truncate(time.Microsecond)
+		attempts, err := t.store.Get(ctx, k.key)
+		if err != nil {
+			return reservedKey{}, err
+		}
+
+		next := entities.LoginAttempts{Key: k.key, Failures: 1, LastFailureAt: now}
+		reserved := reservedKey{key: k.key, reservedAt: now, previousFailureAt: now}
+		if attempts != nil {
+			until, lockout := t.blockedUntil(attempts.Failures, attempts.LastFailureAt, k.threshold)
+			if retryAfter := until.Sub(now); retryAfter > 0 {
+				return reservedKey{}, &LoginThrottledError{RetryAfter: retryAfter, Scope: k.scope, Lockout: lockout}
+			}
+
+			// Счетчик начинается заново, если с последней неудачной попытки прошло больше окна наблюдения
+			if !attempts.LastFailureAt.Before(now.Add(-t.policy.LockoutDuration)) {
+				next.Failures = attempts.Failures + 1
+			}
+			reserved.previousFailureAt = attempts.LastFailureAt
+		}
+
+		swapped, err := t.store.CompareAndSwap(ctx, attempts, next)
+		if err != nil {
+			return reservedKey{}, err
+		}
+		if swapped {
+			return reserved, nil
+		}
+	}
+
+	// Счетчик все время меняют одновременные попытки: отклоняем эту, как при задержке
+	return reservedKey{}, &LoginThrottledError{RetryAfter: max(t.policy.BaseDelay, time.Second), Scope: k.scope}
+}
+
+// keys возвращает счетчики, по которым ограничивается вход
+func (t *LoginThrottler) keys(email, ip string) []throttleKey {
+	keys := []throttleKey{{
+		key:       t.namespace + accountThrottleKey(email),
+		scope:     ThrottleScopeAccount,
+		threshold: t.policy.AccountLockoutThreshold,
+	}}
+	if ip != "" {
+		keys = append(keys, throttleKey{
+			key:       t.namespace + "ip:" + ip,
+			scope:     ThrottleScopeIP,
+			threshold: t.policy.IPLockoutThreshold,
+		})
+	}
+	return keys
+}
+
+// blockedUntil вычисляет, до какого момента следующая попытка будет отклонена
+func (t *LoginThrottler) blockedUntil(failures int, lastFailureAt time.Time, threshold int) (time.Time, bool) {
+	if threshold > 0 && failures >= threshold {
+		return lastFailureAt.Add(t.policy.LockoutDuration), true
+	}
+	if failures <= t.policy.FreeAttempts {
+		return time.Time{}, false
+	}
+
+	delay := t.policy.MaxDelay
+	if exp := failures - t.policy.FreeAttempts - 1; exp < 32 {
+		if d := t.policy.BaseDelay << exp; d > 0 && d < delay {
+			delay = d
+		}
+	}
+	return lastFailureAt.Add(delay), false
+}
+
+// accountThrottleKey строит ключ счетчика аккаунта. Для несуществующих email счетчик ведется так же,
+// чтобы по блокировке нельзя было определить, зарегистрирован ли адрес.
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,395 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/infrastructure/memory"
+)
+
+const (
+	testEmail = "user@example.com"
+	testIP    = "192.0.2.1"
+)
+
+var testThrottlePolicy = services.LoginThrottlePolicy{
+	FreeAttempts:            2,
+	BaseDelay:               time.Hour,
+	MaxDelay:                4 * time.Hour,
+	AccountLockoutThreshold: 5,
+	IPLockoutThreshold:      8,
+	LockoutDuration:         24 * time.Hour,
+}
+
+// lockoutTestPolicy не откладывает попытки до блокировки
+var lockoutTestPolicy = services.LoginThrottlePolicy{
+	FreeAttempts:            10,
+	BaseDelay:               time.Hour,
+	MaxDelay:                4 * time.Hour,
+	AccountLockoutThreshold: 5,
+	IPLockoutThreshold:      8,
+	LockoutDuration:         24 * time.Hour,
+}
+
+func TestLoginThrottlerReserve(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      services.LoginThrottlePolicy
+		setup       func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler)
+		email       string
+		wantScope   string // Пусто, если попытка должна быть разрешена
+		wantLockout bool
+	}{
+		{
+			name:   "first attempt is allowed",
+			policy: testThrottlePolicy,
+			email:  testEmail,
+		},
+		{
+			name:   "attempts within free limit are allowed",
+			policy: testThrottlePolicy,
+			setup: func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler) {
+				failAttempts(ctx, t, throttler, testEmail, testThrottlePolicy.FreeAttempts-1)
+			},
+			email: testEmail,
+		},
+		{
+			name:   "attempt after free limit is delayed",
+			policy: testThrottlePolicy,
+			setup: func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler) {
+				failAttempts(ctx, t, throttler, testEmail, testThrottlePolicy.FreeAttempts+1)
+			},
+			email:     testEmail,
+			wantScope: services.ThrottleScopeAccount,
+		},
+		{
+			name:   "released attempts are not counted",
+			policy: testThrottlePolicy,
+			setup: func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler) {
+				for i := 0; i < testThrottlePolicy.AccountLockoutThreshold; i++ {
+					reservation := reserve(ctx, t, throttler, testEmail)
+					if err := throttler.Release(ctx, reservation); err != nil {
+						t.Fatalf("Release() error = %v", err)
+					}
+				}
+			},
+			email: testEmail,
+		},
+		{
+			name:   "account lockout after threshold",
+			policy: lockoutTestPolicy,
+			setup: func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler) {
+				failAttempts(ctx, t, throttler, testEmail, lockoutTestPolicy.AccountLockoutThreshold)
+			},
+			email:       testEmail,
+			wantScope:   services.ThrottleScopeAccount,
+			wantLockout: true,
+		},
+		{
+			name:   "ip lockout across accounts",
+			policy: lockoutTestPolicy,
+			setup: func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler) {
+				for i := 0; i < lockoutTestPolicy.IPLockoutThreshold; i++ {
+					failAttempts(ctx, t, throttler, string(rune('a'+i))+"@example.com", 1)
+				}
+			},
+			email:       "other@example.com",
+			wantScope:   services.ThrottleScopeIP,
+			wantLockout: true,
+		},
+		{
+			name:   "account counter ignores email case",
+			policy: testThrottlePolicy,
+			setup: func(ctx context.Context, t *testing.T, throttler *services.LoginThrottler) {
+				failAttempts(ctx, t, throttler, "USER@example.com", testThrottlePolicy.FreeAttempts+1)
+			},
+			email:     testEmail,
+			wantScope: services.ThrottleScopeAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			throttler := services.NewLoginThrottler(memory.NewLoginAttemptStore(), tt.policy)
+			if tt.setup != nil {
+				tt.setup(ctx, t, throttler)
+			}
+
+			_, err := throttler.Reserve(ctx, tt.email, testIP)
+			if tt.wantScope == "" {
+				if err != nil {
+					t.Fatalf("Reserve() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var throttled *services.LoginThrottledError
+			if !errors.As(err, &throttled) {
+				t.Fatalf("Reserve() error = %v, want *LoginThrottledError", err)
+			}
+			if throttled.Scope != tt.wantScope || throttled.Lockout != tt.wantLockout {
+				t.Errorf("Reserve() scope = %q, lockout = %v, want %q, %v", throttled.Scope, throttled.Lockout, tt.wantScope, tt.wantLockout)
+			}
+			if throttled.RetryAfter <= 0 {
+				t.Errorf("Reserve() RetryAfter = %v, want positive", throttled.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlerRejectedReserveKeepsCounters(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewLoginAttemptStore()
+	throttler := services.NewLoginThrottler(store, lockoutTestPolicy)
+
+	failAttempts(ctx, t, throttler, testEmail, lockoutTestPolicy.AccountLockoutThreshold)
+	before := getAttempts(ctx, t, store, "ip:"+testIP)
+
+	if _, err := throttler.Reserve(ctx, testEmail, testIP); err == nil {
+		t.Fatal("Reserve() error = nil, want *LoginThrottledError")
+	}
+
+	after := getAttempts(ctx, t, store, "ip:"+testIP)
+	if after.Failures != before.Failures || !after.LastFailureAt.Equal(before.LastFailureAt) {
+		t.Errorf("ip counter = %+v, want unchanged %+v", after, before)
+	}
+}
+
+func TestLoginThrottlerRelease(t *testing.T) {
+	tests := []struct {
+		name            string
+		previous        int  // Неудачные попытки до проверяемой
+		laterAttempt    bool // После проверяемой попытки учтена еще одна
+		wantFailures    int
+		wantRestoreTime bool // Время последней неудачи возвращается к предыдущей попытке
+	}{
+		{
+			name:         "first attempt",
+			wantFailures: 0,
+		},
+		{
+			name:            "restores previous failure time",
+			previous:        2,
+			wantFailures:    2,
+			wantRestoreTime: true,
+		},
+		{
+			name:         "keeps later attempt time",
+			previous:     1,
+			laterAttempt: true,
+			wantFailures: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := memory.NewLoginAttemptStore()
+			throttler := services.NewLoginThrottler(store, testThrottlePolicy)
+
+			keys := []string{"account:" + testEmail, "ip:" + testIP}
+			lastFailures := func() map[string]time.Time {
+				result := make(map[string]time.Time)
+				for _, key := range keys {
+					result[key] = getAttempts(ctx, t, store, key).LastFailureAt
+				}
+				return result
+			}
+
+			failAttempts(ctx, t, throttler, testEmail, tt.previous)
+			var previousFailureAt map[string]time.Time
+			if tt.previous > 0 {
+				previousFailureAt = lastFailures()
+			}
+
+			// Паузы различают время попыток, которое хранится с точностью до микросекунд
+			time.Sleep(time.Millisecond)
+			reservation := reserve(ctx, t, throttler, testEmail)
+			var laterFailureAt map[string]time.Time
+			if tt.laterAttempt {
+				time.Sleep(time.Millisecond)
+				reserve(ctx, t, throttler, testEmail)
+				laterFailureAt = lastFailures()
+			}
+
+			if err := throttler.Release(ctx, reservation); err != nil {
+				t.Fatalf("Release() error = %v", err)
+			}
+
+			for _, key := range keys {
+				attempts := getAttempts(ctx, t, store, key)
+				if attempts.Failures != tt.wantFailures {
+					t.Errorf("%s failures = %d, want %d", key, attempts.Failures, tt.wantFailures)
+				}
+				if tt.wantRestoreTime && !attempts.LastFailureAt.Equal(previousFailureAt[key]) {
+					t.Errorf("%s last failure = %v, want %v", key, attempts.LastFailureAt, previousFailureAt[key])
+				}
+				if tt.laterAttempt && !attempts.LastFailureAt.Equal(laterFailureAt[key]) {
+					t.Errorf("%s last failure = %v, want %v", key, attempts.LastFailureAt, laterFailureAt[key])
+				}
+			}
+		})
+	}
+}
+
+func TestLoginThrottlerRecordFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        services.LoginThrottlePolicy
+		failures      int
+		wantThrottled bool   // Следующая попытка откладывается
+		wantScope     string // Пусто, если задержка наступает по обоим счетчикам
+		wantLockout   bool
+	}{
+		{
+			name:     "within free limit",
+			policy:   testThrottlePolicy,
+			failures: testThrottlePolicy.FreeAttempts,
+		},
+		{
+			name:          "delay triggered",
+			policy:        testThrottlePolicy,
+			failures:      testThrottlePolicy.FreeAttempts + 1,
+			wantThrottled: true,
+		},
+		{
+			name:          "lockout triggered",
+			policy:        lockoutTestPolicy,
+			failures:      lockoutTestPolicy.AccountLockoutThreshold,
+			wantThrottled: true,
+			wantScope:     services.ThrottleScopeAccount,
+			wantLockout:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			throttler := services.NewLoginThrottler(memory.NewLoginAttemptStore(), tt.policy)
+
+			failAttempts(ctx, t, throttler, testEmail, tt.failures-1)
+			reserve(ctx, t, throttler, testEmail)
+			err := throttler.RecordFailure(ctx, testEmail, testIP)
+
+			if !tt.wantThrottled {
+				if err != nil {
+					t.Fatalf("RecordFailure() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var throttled *services.LoginThrottledError
+			if !errors.As(err, &throttled) {
+				t.Fatalf("RecordFailure() error = %v, want *LoginThrottledError", err)
+			}
+			if !throttled.Triggered || throttled.Lockout != tt.wantLockout {
+				t.Errorf("RecordFailure() triggered = %v, lockout = %v, want true, %v", throttled.Triggered, throttled.Lockout, tt.wantLockout)
+			}
+			if tt.wantScope != "" && throttled.Scope != tt.wantScope {
+				t.Errorf("RecordFailure() scope = %q, want %q", throttled.Scope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlerConcurrentReserve(t *testing.T) {
+	const attempts = 50
+
+	ctx := context.Background()
+	store := memory.NewLoginAttemptStore()
+	throttler := services.NewLoginThrottler(store, testThrottlePolicy)
+
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		reservations []*services.LoginReservation
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reservation, err := throttler.Reserve(ctx, testEmail, testIP)
+			if err != nil {
+				var throttled *services.LoginThrottledError
+				if !errors.As(err, &throttled) {
+					t.Errorf("Reserve() error = %v", err)
+				}
+				return
+			}
+			mu.Lock()
+			reservations = append(reservations, reservation)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Проходят только попытки без задержки: каждая видит попытки, учтенные до нее
+	if want := testThrottlePolicy.FreeAttempts + 1; len(reservations) != want {
+		t.Fatalf("allowed attempts = %d, want %d", len(reservations), want)
+	}
+	if got := getAttempts(ctx, t, store, "account:"+testEmail).Failures; got != len(reservations) {
+		t.Fatalf("account failures = %d, want %d", got, len(reservations))
+	}
+
+	for _, reservation := range reservations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := throttler.Release(ctx, reservation); err != nil {
+				t.Errorf("Release() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, key := range []string{"account:" + testEmail, "ip:" + testIP} {
+		if got := getAttempts(ctx, t, store, key).Failures; got != 0 {
+			t.Errorf("%s failures = %d, want 0", key, got)
+		}
+	}
+	if _, err := throttler.Reserve(ctx, testEmail, testIP); err != nil {
+		t.Errorf("Reserve() after release error = %v, want nil", err)
+	}
+}
+
+// failAttempts учитывает n неудачных попыток входа
+func failAttempts(ctx context.Context, t *testing.T, throttler *services.LoginThrottler, email string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		reserve(ctx, t, throttler, email)
+		if err := throttler.RecordFailure(ctx, email, testIP); err != nil {
+			var throttled *services.LoginThrottledError
+			if !errors.As(err, &throttled) {
+				t.Fatalf("RecordFailure() error = %v", err)
+			}
+		}
+	}
+}
+
+func reserve(ctx context.Context, t *testing.T, throttler *services.LoginThrottler, email string) *services.LoginReservation {
+	t.Helper()
+	reservation, err := throttler.Reserve(ctx, email, testIP)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	return reservation
+}
+
+func getAttempts(ctx context.Context, t *testing.T, store repositories.LoginAttemptStore, key string) entities.LoginAttempts {
+	t.Helper()
+	attempts, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+	if attempts == nil {
+		t.Fatalf("Get(%q) = nil, want counter", key)
+	}
+	return *attempts
+}
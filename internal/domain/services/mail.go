@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+)
+
+// MailMessage описывает письмо пользователю
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MailSender отправляет письма пользователям
+type MailSender interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
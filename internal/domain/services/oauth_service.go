@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var (
+	ErrUnknownIdentityProvider = errors.New("unknown identity provider")
+	ErrInvalidOAuthState       = errors.New("invalid or expired authorization state")
+	ErrOAuthExchangeFailed     = errors.New("failed to complete sign-in with identity provider")
+	ErrOAuthEmailRequired      = errors.New("identity provider did not return an email address")
+	ErrOAuthEmailNotVerified   = errors.New("an account with this email already exists, but the identity provider has not verified the email")
+	ErrOAuthAccountNotVerified = errors.New("an account with this email already exists, but its email has not been verified; sign in with the password and verify the email first")
+)
+
+// ExternalIdentity содержит сведения о пользователе, подтвержденные внешним провайдером
+type ExternalIdentity struct {
+	Subject       string // Постоянный идентификатор пользователя у провайдера
+	Email         string
+	EmailVerified bool
+}
+
+// IdentityProvider описывает внешнего провайдера входа по authorization code flow с PKCE
+type IdentityProvider interface {
+	// Name возвращает имя провайдера, используемое в адресах API
+	Name() string
+
+	// AuthCodeURL возвращает адрес страницы входа провайдера
+	AuthCodeURL(ctx context.Context, state, codeChallenge, nonce string) (string, error)
+
+	// Exchange обменивает код авторизации на подтвержденные сведения о пользователе
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
+
+type OAuthService struct {
+	userRepo     repositories.UserRepository
+	identityRepo repositories.UserIdentityRepository
+	stateRepo    repositories.OAuthStateRepository
+	authService  *AuthService
+	providers    map[string]IdentityProvider
+	stateTTL     time.Duration
+}
+
+func NewOAuthService(
+	userRepo repositories.UserRepository,
+	identityRepo repositories.UserIdentityRepository,
+	stateRepo repositories.OAuthStateRepository,
+	authService *AuthService,
+	stateTTL time.Duration,
+	providers ...IdentityProvider,
+) *OAuthService {
+	providersByName := make(map[string]IdentityProvider, len(providers))
+	for _, provider := range providers {
+		providersByName[provider.Name()] = provider
+	}
+
+	return &OAuthService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		authService:  authService,
+		providers:    providersByName,
+		stateTTL:     stateTTL,
+	}
+}
+
+// Start начинает вход через провайдера и возвращает адрес страницы входа.
+// state, PKCE code_verifier и nonce сохраняются до получения ответа провайдера.
+func (s *OAuthService) Start(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", ErrUnknownIdentityProvider
+	}
+
+	state, err := generateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := generateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := generateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.stateRepo.Create(ctx, entities.NewOAuthState(hashToken(state), providerName, codeVerifier, nonce, s.stateTTL)); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(ctx, state, pkceChallenge(codeVerifier), nonce)
+}
+
+// Complete обрабатывает ответ провайдера: обменивает код на сведения о пользователе,
+// находит или создает связанного пользователя и выполняет вход так же, как AuthService.Login.
+func (s *OAuthService) Complete(ctx context.Context, providerName, state, code string, client ClientInfo) (*LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownIdentityProvider
+	}
+
+	// Состояние одноразовое: удаляем его до обмена кода, чтобы ответ нельзя было обработать повторно
+	stored, err := s.stateRepo.Consume(ctx, hashToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.Provider != providerName || stored.IsExpired() {
+		return nil, ErrInvalidOAuthState
+	}
+
+	external, err := provider.Exchange(ctx, code, stored.CodeVerifier, stored.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthExchangeFailed, err)
+	}
+
+	user, err := s.resolveUser(ctx, providerName, external)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.LoginWithIdentity(ctx, user, client)
+}
+
+// PurgeExpiredStates удаляет состояния входов, которые не были завершены
+func (s *OAuthService) PurgeExpiredStates(ctx context.Context) error {
+	return s.stateRepo.DeleteExpired(ctx, time.Now())
+}
+
+// ExportSection возвращает имя раздела внешних учетных записей в экспорте аккаунта
+func (s *OAuthService) ExportSection() string {
+	return "identities"
+}
+
+// ExportUserData возвращает привязанные внешние учетные записи для экспорта аккаунта
+func (s *OAuthService) ExportUserData(ctx context.Context, userID int) (interface{}, error) {
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+// resolveUser находит пользователя по привязанной учетной записи. Если привязки нет, учетная запись
+// привязывается к пользователю с тем же email, но только если email подтвердили и провайдер, и сам
+// пользователь. Если такого пользователя нет, создается новый.
+func (s *OAuthService) resolveUser(ctx context.Context, providerName string, external *ExternalIdentity) (*entities.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, providerName, external.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, ErrInvalidCredentials
+		}
+		return user, nil
+	}
+
+	email := strings.ToLower(strings.TrimSpace(external.Email))
+	if email == "" {
+		return nil, ErrOAuthEmailRequired
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		// Без подтверждения email у провайдера привязка позволила бы захватить чужой аккаунт
+		if !external.EmailVerified {
+			return nil, ErrOAuthEmailNotVerified
+		}
+		// Неподтвержденный аккаунт мог зарегистрировать кто угодно, и его пароль остался бы у него
+		if !user.IsEmailVerified() {
+			return nil, ErrOAuthAccountNotVerified
+		}
+	case errors.Is(err, repositories.ErrUserNotFound):
+		if user, err = s.createUser(ctx, email); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	// Email нового пользователя подтвержден провайдером
+	if external.EmailVerified && !user.IsEmailVerified() {
+		if _, err := s.userRepo.MarkEmailVerified(ctx, user.ID, email); err != nil {
+			return nil, err
+		}
+		if user, err = s.userRepo.GetByID(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.identityRepo.Create(ctx, &entities.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  external.Subject,
+		Email:    email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser регистрирует пользователя, впервые вошедшего через провайдера.
+// Пароль случайный и никому не известен; при необходимости его можно задать через сброс пароля.
+func (s *OAuthService) createUser(ctx context.Context, email string) (*entities.User, error) {
+	password, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := s.authService.passwords.Rehash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := entities.NewUser(email, passwordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Если email занят удаленным аккаунтом или пользователь создан параллельно, вернется ErrEmailAlreadyExists
+	return s.userRepo.Create(ctx, user)
+}
+
+// pkceChallenge вычисляет code_challenge по методу S256 (RFC 7636)
+func pkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
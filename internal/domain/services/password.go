@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrPasswordBreached = errors.New("password is too common or has appeared in a data breach")
+
+// PasswordPolicyError возвращается, если новый пароль не соответствует политике паролей
+type PasswordPolicyError struct {
+	Err error
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return e.Err
+}
+
+// PasswordHasher хеширует и проверяет пароли.
+// Хеш хранится в формате PHC ($<алгоритм>$...), чтобы по нему можно было определить алгоритм и параметры.
+type PasswordHasher interface {
+	// Hash хеширует пароль текущим алгоритмом
+	Hash(password string) (string, error)
+
+	// Verify проверяет пароль против хеша. needsRehash сообщает, что хеш получен
+	// устаревшим алгоритмом или параметрами и его стоит пересчитать
+	Verify(password, encodedHash string) (ok bool, needsRehash bool)
+}
+
+// PasswordPolicy задает требования к новым паролям
+type PasswordPolicy struct {
+	MinLength int
+	Breached  map[string]struct{} // Пароли из утечек, которые нельзя устанавливать
+}
+
+// NewPasswordPolicy создает политику паролей со списком запрещенных паролей
+func NewPasswordPolicy(minLength int, breached []string) PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength: minLength,
+		Breached:  make(map[string]struct{}, len(breached)),
+	}
+	for _, password := range breached {
+		policy.Breached[password] = struct{}{}
+	}
+	return policy
+}
+
+// Validate проверяет новый пароль и возвращает *PasswordPolicyError, если он не подходит.
+// Существующие пароли политикой не проверяются
+func (p PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return &PasswordPolicyError{Err: fmt.Errorf("password must be at least %d characters long", p.MinLength)}
+	}
+
+	if _, found := p.Breached[password]; found {
+		return &PasswordPolicyError{Err: ErrPasswordBreached}
+	}
+
+	return nil
+}
+
+// PasswordManager объединяет хеширование паролей и политику для новых паролей
+type PasswordManager struct {
+	hasher PasswordHasher
+	policy PasswordPolicy
+}
+
+func NewPasswordManager(hasher PasswordHasher, policy PasswordPolicy) *PasswordManager {
+	return &PasswordManager{
+		hasher: hasher,
+		policy: policy,
+	}
+}
+
+// HashNew проверяет новый пароль на соответствие политике и хеширует его
+func (m *PasswordManager) HashNew(password string) (string, error) {
+	if err := m.policy.Validate(password); err != nil {
+		return "", err
+	}
+
+	return m.hasher.Hash(password)
+}
+
+// Verify проверяет пароль против хеша и сообщает, нужно ли пересчитать хеш
+func (m *PasswordManager) Verify(password, encodedHash string) (ok bool, needsRehash bool) {
+	return m.hasher.Verify(password, encodedHash)
+}
+
+// Rehash пересчитывает хеш уже проверенного пароля текущим алгоритмом. Политика не применяется,
+// чтобы пользователи со старыми короткими паролями могли войти
+func (m *PasswordManager) Rehash(password string) (string, error) {
+	return m.hasher.Hash(password)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+	// ErrPasswordResetQueueFull возвращается, если очередь запросов сброса заполнена и запрос отброшен
+	ErrPasswordResetQueueFull = errors.New("password reset queue is full")
+)
+
+const (
+	// passwordResetQueueSize ограничивает число запросов сброса, ожидающих обработки
+	passwordResetQueueSize = 100
+	// passwordResetRequestTimeout ограничивает обработку одного запроса сброса
+	passwordResetRequestTimeout = 30 * time.Second
+)
+
+type PasswordResetService struct {
+	userRepo    repositories.UserRepository
+	resetRepo   repositories.PasswordResetTokenRepository
+	authService *AuthService
+	mailer      MailSender
+	throttler   *LoginThrottler
+	queue       chan string
+	tokenTTL    time.Duration
+	resetURL    string
+}
+
+// NewPasswordResetService создает сервис сброса пароля. throttler ограничивает частоту запросов
+// сброса для одного email и одного IP; каждый запрос учитывается как попытка
+func NewPasswordResetService(
+	userRepo repositories.UserRepository,
+	resetRepo repositories.PasswordResetTokenRepository,
+	authService *AuthService,
+	mailer MailSender,
+	throttler *LoginThrottler,
+	tokenTTL time.Duration,
+	resetURL string,
+) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		authService: authService,
+		mailer:      mailer,
+		throttler:   throttler,
+		queue:       make(chan string, passwordResetQueueSize),
+		tokenTTL:    tokenTTL,
+		resetURL:    resetURL,
+	}
+}
+
+// EnqueueReset ставит запрос сброса пароля в очередь, которую обрабатывает Run. Время ответа
+// не зависит от того, существует ли аккаунт. При слишком частых запросах для email или с адреса ip
+// возвращает *LoginThrottledError, а если очередь заполнена - ErrPasswordResetQueueFull
+func (s *PasswordResetService) EnqueueReset(ctx context.Context, email, ip string) error {
+	// Попытка не возвращается: ограничивается число запросов, а не неудачных запросов
+	if _, err := s.throttler.Reserve(ctx, email, ip); err != nil {
+		return err
+	}
+
+	select {
+	case s.queue <- email:
+		return nil
+	default:
+		return ErrPasswordResetQueueFull
+	}
+}
+
+// Run обрабатывает очередь запросов сброса, пока не отменен ctx. Ошибки передаются в onError
+func (s *PasswordResetService) Run(ctx context.Context, onError func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case email := <-s.queue:
+			requestCtx, cancel := context.WithTimeout(ctx, passwordResetRequestTimeout)
+			if err := s.RequestReset(requestCtx, email); err != nil {
+				onError(err)
+			}
+			cancel()
+		}
+	}
+}
+
+// RequestReset отправляет пользователю письмо со ссылкой для сброса пароля.
+// Для неизвестного email ошибка не возвращается, чтобы не раскрывать наличие аккаунта.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	// Действует только последний выданный токен
+	if err := s.resetRepo.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.resetRepo.Create(ctx, entities.NewPasswordResetToken(user.ID, hashToken(token), s.tokenTTL)); err != nil {
+		return err
+	}
+
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, MailMessage{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Чтобы задать новый пароль, перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %d мин. Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+			link, int(s.tokenTTL.Minutes())),
+	})
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса и завершает все сессии пользователя
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	resetToken, err := s.resetRepo.GetByHash(ctx, hashToken(token))
+	if err != nil || !resetToken.IsUsable() {
+		return ErrInvalidResetToken
+	}
+
+	// Проверяем новый пароль до того, как токен будет израсходован
+	passwordHash, err := s.authService.passwords.HashNew(newPassword)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.resetRepo.MarkUsed(ctx, resetToken.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, resetToken.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	user.PasswordHash = passwordHash
+	user.TouchUpdatedAt()
+	if _, err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.authService.LogoutAll(ctx, user.ID)
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var (
+	ErrPhotoTooLarge        = errors.New("photo file is too large")
+	ErrUnsupportedImageType = errors.New("unsupported image type, use JPEG or PNG")
+	ErrInvalidImage         = errors.New("file is not a valid image")
+	ErrImageDimensions      = errors.New("image dimensions are too large")
+	ErrTooManyPhotos        = errors.New("photo limit reached for this profile")
+	ErrInvalidPhotoOrder    = errors.New("photo order must list every photo of the profile exactly once")
+)
+
+// detachedPhotosBatch - сколько отвязанных фотографий удаляется за один запуск фоновой задачи
+const detachedPhotosBatch = 100
+
+// BlobStorage хранит файлы и выдает адреса для их загрузки клиентами
+type BlobStorage interface {
+	// Put сохраняет файл под ключом, перезаписывая существующий
+	Put(ctx context.Context, key, contentType string, data []byte) error
+
+	// Delete удаляет файл. Отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, key string) error
+
+	// URL возвращает адрес файла для клиентов
+	URL(key string) string
+}
+
+// ImageVariant содержит закодированное изображение
+type ImageVariant struct {
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// ProcessedImage содержит исходное изображение и его уменьшенные копии по именам
+type ProcessedImage struct {
+	Original   ImageVariant
+	Thumbnails map[string]ImageVariant
+}
+
+// ImageProcessor проверяет загруженные изображения и готовит их к хранению
+type ImageProcessor interface {
+	// Process проверяет формат и размеры изображения, перекодирует его без метаданных
+	// (в том числе геолокации) и создает уменьшенные копии.
+	// Возвращает ErrUnsupportedImageType, ErrInvalidImage или ErrImageDimensions
+	Process(data []byte, thumbnails []entities.PhotoThumbnail) (*ProcessedImage, error)
+}
+
+type PhotoService struct {
+	profileRepo   repositories.ProfileRepository
+	photoRepo     repositories.ProfilePhotoRepository
+	storage       BlobStorage
+	processor     ImageProcessor
+	maxSize       int64
+	maxPerProfile int
+}
+
+func NewPhotoService(
+	profileRepo repositories.ProfileRepository,
+	photoRepo repositories.ProfilePhotoRepository,
+	storage BlobStorage,
+	processor ImageProcessor,
+	maxSize int64,
+	maxPerProfile int,
+) *PhotoService {
+	return &PhotoService{
+		profileRepo:   profileRepo,
+		photoRepo:     photoRepo,
+		storage:       storage,
+		processor:     processor,
+		maxSize:       maxSize,
+		maxPerProfile: maxPerProfile,
+	}
+}
+
+// MaxSize возвращает максимальный размер загружаемого файла в байтах
+func (s *PhotoService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload добавляет фотографию в анкету пользователя. Первая фотография становится основной
+func (s *PhotoService) Upload(ctx context.Context, userID int, data []byte) (*entities.ProfilePhoto, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrPhotoTooLarge
+	}
+
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.photoRepo.Count(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.maxPerProfile {
+		return nil, ErrTooManyPhotos
+	}
+
+	processed, err := s.processor.Process(data, entities.PhotoThumbnails)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix, err := generateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &entities.ProfilePhoto{
+		ProfileID:   profile.ID,
+		StorageKey:  fmt.Sprintf("profiles/%d/%s", profile.ID, suffix),
+		ContentType: processed.Original.ContentType,
+		SizeBytes:   int64(len(processed.Original.Data)),
+		Width:       processed.Original.Width,
+		Height:      processed.Original.Height,
+	}
+
+	if err := s.storeVariants(ctx, photo, processed); err != nil {
+		s.deleteFiles(ctx, photo)
+		return nil, err
+	}
+
+	created, err := s.photoRepo.Create(ctx, photo)
+	if err != nil {
+		s.deleteFiles(ctx, photo)
+		return nil, err
+	}
+
+	if err := s.profileRepo.BumpVersion(ctx, profile.ID); err != nil {
+		return nil, err
+	}
+
+	s.setURLs(created)
+	return created, nil
+}
+
+// List возвращает фотографии анкеты пользователя в порядке показа
+func (s *PhotoService) List(ctx context.Context, userID int) ([]*entities.ProfilePhoto, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listByProfile(ctx, profile.ID)
+}
+
+// Reorder задает порядок показа фотографий. photoIDs должен перечислять все фотографии анкеты
+func (s *PhotoService) Reorder(ctx context.Context, userID int, photoIDs []int) ([]*entities.ProfilePhoto, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := s.photoRepo.ListByProfile(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !samePhotoSet(photos, photoIDs) {
+		return nil, ErrInvalidPhotoOrder
+	}
+
+	if err := s.photoRepo.Reorder(ctx, profile.ID, photoIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.BumpVersion(ctx, profile.ID); err != nil {
+		return nil, err
+	}
+
+	return s.listByProfile(ctx, profile.ID)
+}
+
+// SetPrimary делает фотографию основной
+func (s *PhotoService) SetPrimary(ctx context.Context, userID, photoID int) ([]*entities.ProfilePhoto, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.photoRepo.SetPrimary(ctx, profile.ID, photoID); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.BumpVersion(ctx, profile.ID); err != nil {
+		return nil, err
+	}
+
+	return s.listByProfile(ctx, profile.ID)
+}
+
+// Delete удаляет фотографию из анкеты. Если удалить файлы сразу не удалось,
+// их удалит PurgeDetachedPhotos
+func (s *PhotoService) Delete(ctx context.Context, userID, photoID int) error {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	photo, err := s.photoRepo.Detach(ctx, profile.ID, photoID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.profileRepo.BumpVersion(ctx, profile.ID); err != nil {
+		return err
+	}
+
+	// Ошибка удаления файлов не мешает удалению фотографии из анкеты:
+	// запись остается отвязанной, и файлы удалит PurgeDetachedPhotos
+	_ = s.deletePhoto(ctx, photo)
+
+	return nil
+}
+
+// PurgeDetachedPhotos удаляет файлы и записи фотографий, отвязанных от анкет
+// при удалении фотографии или анкеты
+func (s *PhotoService) PurgeDetachedPhotos(ctx context.Context) error {
+	photos, err := s.photoRepo.ListDetached(ctx, detachedPhotosBatch)
+	if err != nil {
+		return err
+	}
+
+	// Ошибка с одной фотографией не должна останавливать удаление остальных
+	var errs []error
+	for _, photo := range photos {
+		if err := s.deletePhoto(ctx, photo); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AttachPhotos заполняет фотографии анкет одним запросом к базе
+func (s *PhotoService) AttachPhotos(ctx context.Context, profiles ...*entities.Profile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	profileIDs := make([]int, len(profiles))
+	for i, profile := range profiles {
+		profileIDs[i] = profile.ID
+	}
+
+	photos, err := s.photoRepo.ListByProfiles(ctx, profileIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		profile.Photos = photos[profile.ID]
+		if profile.Photos == nil {
+			profile.Photos = []*entities.ProfilePhoto{}
+		}
+		for _, photo := range profile.Photos {
+			s.setURLs(photo)
+		}
+	}
+
+	return nil
+}
+
+// ExportSection возвращает имя раздела фотографий в экспорте аккаунта
+func (s *PhotoService) ExportSection() string {
+	return "photos"
+}
+
+// ExportUserData возвращает фотографии анкеты с адресами файлов для экспорта аккаунта
+func (s *PhotoService) ExportUserData(ctx context.Context, userID int) (interface{}, error) {
+	photos, err := s.List(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrProfileNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// listByProfile возвращает фотографии анкеты с адресами файлов
+func (s *PhotoService) listByProfile(ctx context.Context, profileID int) ([]*entities.ProfilePhoto, error) {
+	photos, err := s.photoRepo.ListByProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, photo := range photos {
+		s.setURLs(photo)
+	}
+
+	return photos, nil
+}
+
+// storeVariants сохраняет исходное изображение и уменьшенные копии в хранилище
+func (s *PhotoService) storeVariants(ctx context.Context, photo *entities.ProfilePhoto, processed *ProcessedImage) error {
+	original := processed.Original
+	if err := s.storage.Put(ctx, photo.VariantKey(entities.PhotoVariantOriginal), original.ContentType, original.Data); err != nil {
+		return err
+	}
+
+	for _, thumbnail := range entities.PhotoThumbnails {
+		variant, ok := processed.Thumbnails[thumbnail.Name]
+		if !ok {
+			return fmt.Errorf("thumbnail %s was not generated", thumbnail.Name)
+		}
+		if err := s.storage.Put(ctx, photo.VariantKey(thumbnail.Name), variant.ContentType, variant.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deletePhoto удаляет файлы отвязанной фотографии, а затем ее запись
+func (s *PhotoService) deletePhoto(ctx context.Context, photo *entities.ProfilePhoto) error {
+	if err := s.deleteFiles(ctx, photo); err != nil {
+		return err
+	}
+
+	return s.photoRepo.DeleteDetached(ctx, photo.ID)
+}
+
+// deleteFiles удаляет все варианты фотографии из хранилища
+func (s *PhotoService) deleteFiles(ctx context.Context, photo *entities.ProfilePhoto) error {
+	var firstErr error
+	for _, key := range photo.VariantKeys() {
+		if err := s.storage.Delete(ctx, key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// setURLs заполняет адреса вариантов фотографии
+func (s *PhotoService) setURLs(photo *entities.ProfilePhoto) {
+	photo.URLs = map[string]string{
+		entities.PhotoVariantOriginal: s.storage.URL(photo.VariantKey(entities.PhotoVariantOriginal)),
+	}
+	for _, thumbnail := range entities.PhotoThumbnails {
+		photo.URLs[thumbnail.Name] = s.storage.URL(photo.VariantKey(thumbnail.Name))
+	}
+}
+
+// samePhotoSet проверяет, что photoIDs перечисляет каждую фотографию ровно один раз
+func samePhotoSet(photos []*entities.ProfilePhoto, photoIDs []int) bool {
+	if len(photos) != len(photoIDs) {
+		return false
+	}
+
+	remaining := make(map[int]bool, len(photos))
+	for _, photo := range photos {
+		remaining[photo.ID] = true
+	}
+
+	for _, id := range photoIDs {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+
+	return true
+}
@@ -9,17 +9,23 @@ import (
 )
 
 type ProfileService struct {
-	profileRepo repositories.ProfileRepository
+	profileRepo     repositories.ProfileRepository
+	photoService    *PhotoService
+	interestService *InterestService
+	friends         FriendProvider
 }
 
-func NewProfileService(profileRepo repositories.ProfileRepository) *ProfileService {
+func NewProfileService(profileRepo repositories.ProfileRepository, photoService *PhotoService, interestService *InterestService, friends FriendProvider) *ProfileService {
 	return &ProfileService{
-		profileRepo: profileRepo,
+		profileRepo:     profileRepo,
+		photoService:    photoService,
+		interestService: interestService,
+		friends:         friends,
 	}
 }
 
 // CreateProfile создает новый профиль
-func (s *ProfileService) CreateProfile(ctx context.Context, userID int, firstName, lastName string, age int, gender, city string, interests []string) (*entities.Profile, error) {
+func (s *ProfileService) CreateProfile(ctx context.Context, userID int, firstName, lastName string, birthDate entities.Date, gender, city string, interests []string) (*entities.Profile, error) {
 	// Проверяем, есть ли уже профиль у пользователя
 	existingProfile, err := s.profileRepo.GetByUserID(ctx, userID)
 	if err == nil && existingProfile != nil {
@@ -27,67 +33,280 @@ func (s *ProfileService) CreateProfile(ctx context.Context, userID int, firstNam
 	}
 
 	// Создаем новый профиль
-	profile, err := entities.NewProfile(userID, firstName, lastName, age, gender, city, interests)
+	profile, err := entities.NewProfile(userID, firstName, lastName, birthDate, gender, city, interests)
 	if err != nil {
 		return nil, err
 	}
 
+	// Заменяем введенные интересы каноническими
+	if profile.Interests, err = s.interestService.Canonicalize(ctx, profile.Interests); err != nil {
+		return nil, err
+	}
+
 	// Сохраняем в базе
-	return s.profileRepo.Create(ctx, profile)
+	return s.withPhotos(ctx)(s.profileRepo.Create(ctx, profile))
 }
 
-// GetProfile получает профиль по ID
-func (s *ProfileService) GetProfile(ctx context.Context, id int) (*entities.Profile, error) {
-	return s.profileRepo.GetByID(ctx, id)
+// GetProfile получает профиль по ID так, как его видит зритель viewerID (0 - неавторизованный):
+// скрытые от зрителя поля очищаются, а скрытая целиком анкета не находится.
+// Вместе с анкетой возвращается отношение зрителя к ее владельцу
+func (s *ProfileService) GetProfile(ctx context.Context, id, viewerID int) (*entities.Profile, entities.ViewerRelation, error) {
+	profile, err := s.withPhotos(ctx)(s.profileRepo.GetByID(ctx, id))
+	if err != nil {
+		return nil, entities.ViewerAnonymous, err
+	}
+
+	relation, err := s.viewerRelation(ctx, profile.UserID, viewerID)
+	if err != nil {
+		return nil, entities.ViewerAnonymous, err
+	}
+
+	view, ok := profile.ViewFor(relation)
+	if !ok {
+		return nil, relation, repositories.ErrProfileNotFound
+	}
+
+	return view, relation, nil
 }
 
 // GetProfileByUserID получает профиль по ID пользователя
 func (s *ProfileService) GetProfileByUserID(ctx context.Context, userID int) (*entities.Profile, error) {
-	return s.profileRepo.GetByUserID(ctx, userID)
+	return s.withPhotos(ctx)(s.profileRepo.GetByUserID(ctx, userID))
 }
 
-// UpdateProfile обновляет профиль
-func (s *ProfileService) UpdateProfile(ctx context.Context, userID int, firstName, lastName string, age int, gender, city string, interests []string) (*entities.Profile, error) {
+// UpdateProfile обновляет профиль. Если expectedVersion не 0, профиль обновляется только
+// при совпадении версии, иначе возвращается repositories.ErrProfileVersionMismatch
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID, expectedVersion int, firstName, lastName string, birthDate entities.Date, gender, city string, interests []string) (*entities.Profile, error) {
 	// Получаем существующий профиль
 	profile, err := s.profileRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("profile not found")
 	}
 
+	if expectedVersion != 0 && profile.Version != expectedVersion {
+		return nil, repositories.ErrProfileVersionMismatch
+	}
+
 	// Обновляем данные
-	err = profile.Update(firstName, lastName, age, gender, city, interests)
+	err = profile.Update(firstName, lastName, birthDate, gender, city, interests)
 	if err != nil {
 		return nil, err
 	}
 
+	if profile.Interests, err = s.interestService.Canonicalize(ctx, profile.Interests); err != nil {
+		return nil, err
+	}
+
 	// Сохраняем изменения
-	return s.profileRepo.Update(ctx, profile)
+	return s.withPhotos(ctx)(s.profileRepo.Update(ctx, profile, expectedVersion))
 }
 
-// SearchProfiles ищет профили по фильтрам
-func (s *ProfileService) SearchProfiles(ctx context.Context, filters repositories.SearchFilters) ([]*entities.Profile, int, error) {
+// PatchProfile частично обновляет профиль: меняются только поля, заданные в patch.
+// expectedVersion проверяется так же, как в UpdateProfile
+func (s *ProfileService) PatchProfile(ctx context.Context, userID, expectedVersion int, patch entities.ProfilePatch) (*entities.Profile, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedVersion != 0 && profile.Version != expectedVersion {
+		return nil, repositories.ErrProfileVersionMismatch
+	}
+
+	if patch.IsEmpty() {
+		return s.withPhotos(ctx)(profile, nil)
+	}
+
+	// Проверяем анкету с примененными изменениями, а сохраняем только измененные поля,
+	// чтобы не перезаписать остальные поля, измененные параллельным запросом
+	if err := profile.ApplyPatch(patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Interests != nil {
+		if profile.Interests, err = s.interestService.Canonicalize(ctx, profile.Interests); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.withPhotos(ctx)(s.profileRepo.Patch(ctx, profile, patch, expectedVersion))
+}
+
+// ProfileSearchResult содержит страницу результатов поиска анкет
+type ProfileSearchResult struct {
+	Profiles   []*entities.Profile
+	Total      int                        // Количество всех найденных анкет
+	Limit      int                        // Размер страницы после ограничения MaxSearchLimit
+	Sort       repositories.SearchSort    // Примененный порядок
+	NextCursor *repositories.SearchCursor // Курсор следующей страницы, nil на последней
+}
+
+// SearchProfiles ищет профили по фильтрам так, как их видит зритель filters.ViewerID.
+// По умолчанию анкеты упорядочены по релевантности, если задан текстовый запрос, иначе от новых
+// к старым; с курсором без сортировки сохраняется сортировка курсора
+func (s *ProfileService) SearchProfiles(ctx context.Context, filters repositories.SearchFilters) (*ProfileSearchResult, error) {
 	// Устанавливаем значения по умолчанию
 	if filters.Limit <= 0 {
-		filters.Limit = 10
+		filters.Limit = repositories.DefaultSearchLimit
 	}
-	if filters.Limit > 100 {
-		filters.Limit = 100 // Ограничиваем максимальное количество
+	if filters.Limit > repositories.MaxSearchLimit {
+		filters.Limit = repositories.MaxSearchLimit // Ограничиваем максимальное количество
 	}
-	if filters.Offset < 0 {
+	if filters.Offset < 0 || filters.Cursor != nil {
 		filters.Offset = 0
 	}
 
+	switch {
+	case filters.Sort == "" && filters.Cursor != nil:
+		filters.Sort = filters.Cursor.Sort
+	case filters.Sort == "" && filters.Query != nil:
+		filters.Sort = repositories.SearchSortRelevance
+	case filters.Sort == "":
+		filters.Sort = repositories.SearchSortNewest
+	}
+	if filters.Sort == repositories.SearchSortRelevance && filters.Query == nil {
+		filters.Sort = repositories.SearchSortNewest
+	}
+	if filters.Cursor != nil && filters.Cursor.Sort != filters.Sort {
+		return nil, repositories.ErrInvalidSearchCursor
+	}
+
+	// Интересы в фильтре могут быть заданы названиями и синонимами
+	interests, err := s.interestService.Lookup(ctx, filters.Interests)
+	if err != nil {
+		return nil, err
+	}
+	filters.Interests = interests
+
+	excludeInterests, err := s.interestService.Lookup(ctx, filters.ExcludeInterests)
+	if err != nil {
+		return nil, err
+	}
+	filters.ExcludeInterests = excludeInterests
+
+	filters.ViewerFriendIDs = nil
+	if filters.ViewerID != 0 {
+		friendIDs, err := s.friends.FriendIDs(ctx, filters.ViewerID)
+		if err != nil {
+			return nil, err
+		}
+		filters.ViewerFriendIDs = friendIDs
+	}
+
 	// Получаем профили
-	profiles, err := s.profileRepo.Search(ctx, filters)
+	profiles, next, err := s.profileRepo.Search(ctx, filters)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+
+	if err := s.photoService.AttachPhotos(ctx, profiles...); err != nil {
+		return nil, err
+	}
+
+	// Анкеты, скрытые от зрителя целиком, отсеяны запросом; здесь очищаются скрытые поля
+	friendIDs := make(map[int]bool, len(filters.ViewerFriendIDs))
+	for _, id := range filters.ViewerFriendIDs {
+		friendIDs[id] = true
+	}
+
+	views := make([]*entities.Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		relation := entities.ViewerRegistered
+		switch {
+		case filters.ViewerID == 0:
+			relation = entities.ViewerAnonymous
+		case profile.UserID == filters.ViewerID:
+			relation = entities.ViewerOwner
+		case friendIDs[profile.UserID]:
+			relation = entities.ViewerFriend
+		}
+
+		if view, ok := profile.ViewFor(relation); ok {
+			views = append(views, view)
+		}
 	}
 
 	// Получаем общее количество
 	total, err := s.profileRepo.Count(ctx, filters)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return profiles, total, nil
+	return &ProfileSearchResult{
+		Profiles:   views,
+		Total:      total,
+		Limit:      filters.Limit,
+		Sort:       filters.Sort,
+		NextCursor: next,
+	}, nil
+}
+
+// UpdatePrivacy меняет настройки видимости анкеты пользователя
+func (s *ProfileService) UpdatePrivacy(ctx context.Context, userID int, patch entities.ProfilePrivacyPatch) (*entities.Profile, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy := entities.DefaultProfilePrivacy()
+	if profile.Privacy != nil {
+		privacy = *profile.Privacy
+	}
+
+	if err := privacy.Apply(patch); err != nil {
+		return nil, err
+	}
+
+	return s.withPhotos(ctx)(s.profileRepo.UpdatePrivacy(ctx, userID, privacy))
+}
+
+// ExportSection возвращает имя раздела анкеты в экспорте аккаунта
+func (s *ProfileService) ExportSection() string {
+	return "profile"
+}
+
+// ExportUserData возвращает анкету пользователя для экспорта аккаунта
+func (s *ProfileService) ExportUserData(ctx context.Context, userID int) (interface{}, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrProfileNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// viewerRelation определяет, кем зритель viewerID (0 - неавторизованный) приходится владельцу анкеты
+func (s *ProfileService) viewerRelation(ctx context.Context, ownerID, viewerID int) (entities.ViewerRelation, error) {
+	switch viewerID {
+	case 0:
+		return entities.ViewerAnonymous, nil
+	case ownerID:
+		return entities.ViewerOwner, nil
+	}
+
+	friends, err := s.friends.AreFriends(ctx, viewerID, ownerID)
+	if err != nil {
+		return entities.ViewerAnonymous, err
+	}
+	if friends {
+		return entities.ViewerFriend, nil
+	}
+
+	return entities.ViewerRegistered, nil
+}
+
+// withPhotos возвращает функцию, которая дополняет результат чтения или изменения анкеты фотографиями
+func (s *ProfileService) withPhotos(ctx context.Context) func(*entities.Profile, error) (*entities.Profile, error) {
+	return func(profile *entities.Profile, err error) (*entities.Profile, error) {
+		if err != nil {
+			return nil, err
+		}
+		if err := s.photoService.AttachPhotos(ctx, profile); err != nil {
+			return nil, err
+		}
+		return profile, nil
+	}
 }
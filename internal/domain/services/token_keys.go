@@ -0,0 +1,34 @@
+package services
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey описывает ключ, которым подписываются новые токены
+type SigningKey struct {
+	ID     string // Значение заголовка kid
+	Method jwt.SigningMethod
+	Key    interface{} // Приватный ключ или секрет HMAC
+}
+
+// VerificationKey описывает ключ, которым проверяются токены
+type VerificationKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Key       interface{} // Публичный ключ или секрет HMAC
+	Published bool        // Публикуется ли ключ в JWKS (симметричные ключи не публикуются)
+}
+
+// KeyProvider предоставляет ключи для подписи и проверки JWT.
+// Несколько ключей проверки позволяют ротировать ключ подписи без простоя:
+// токены, подписанные старым ключом, остаются валидными до истечения срока.
+type KeyProvider interface {
+	// SigningKey возвращает текущий ключ подписи
+	SigningKey() SigningKey
+
+	// VerificationKey возвращает ключ проверки по kid
+	VerificationKey(kid string) (VerificationKey, bool)
+
+	// VerificationKeys возвращает все активные ключи проверки
+	VerificationKeys() []VerificationKey
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+type TwoFactorService struct {
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+	issuer        string
+}
+
+// TwoFactorEnrollment содержит данные для добавления аккаунта в приложение-аутентификатор
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+func NewTwoFactorService(userRepo repositories.UserRepository, twoFactorRepo repositories.TwoFactorRepository, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		issuer:        issuer,
+	}
+}
+
+// Enroll генерирует новый секрет. 2FA включается только после подтверждения первым кодом.
+func (s *TwoFactorService) Enroll(ctx context.Context, userID int) (*TwoFactorEnrollment, error) {
+	if enabled, err := s.IsEnabled(ctx, userID); err != nil {
+		return nil, err
+	} else if enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.twoFactorRepo.SaveSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totpURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm включает 2FA после проверки первого кода и возвращает одноразовые коды восстановления
+func (s *TwoFactorService) Confirm(ctx context.Context, userID int, code string) ([]string, error) {
+	settings, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if settings.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := validateTOTP(settings.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = generateRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashToken(codes[i])
+	}
+
+	enabled, err := s.twoFactorRepo.Enable(ctx, userID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return codes, nil
+}
+
+// Disable отключает 2FA. Пароль и код проверяет вызывающая сторона (см. AccountService.DisableTwoFactor).
+func (s *TwoFactorService) Disable(ctx context.Context, userID int) error {
+	return s.twoFactorRepo.Disable(ctx, userID)
+}
+
+// IsEnabled проверяет, включена ли у пользователя 2FA
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID int) (bool, error) {
+	settings, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		// Ошибку нельзя трактовать как выключенную 2FA, иначе сбой БД позволит войти без второго фактора
+		return false, err
+	}
+
+	return settings != nil && settings.IsEnabled(), nil
+}
+
+// VerifyCode проверяет TOTP код или код восстановления.
+// Каждый код может быть использован только один раз.
+func (s *TwoFactorService) VerifyCode(ctx context.Context, userID int, code string) error {
+	settings, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if settings == nil || !settings.IsEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := validateTOTP(settings.Secret, code, time.Now()); ok {
+		used, err := s.twoFactorRepo.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// ExportSection возвращает имя раздела настроек 2FA в экспорте аккаунта
+func (s *TwoFactorService) ExportSection() string {
+	return "two_factor"
+}
+
+// ExportUserData возвращает настройки 2FA для экспорта аккаунта. Секрет и коды восстановления не выгружаются
+func (s *TwoFactorService) ExportUserData(ctx context.Context, userID int) (interface{}, error) {
+	settings, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil || settings == nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// generateRecoveryCode генерирует код восстановления вида xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(totpEncoding.EncodeToString(buf))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode приводит введенный код восстановления к каноничному виду
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) == 10 && !strings.Contains(code, "-") {
+		code = code[:5] + "-" + code[5:]
+	}
+	return code
+}
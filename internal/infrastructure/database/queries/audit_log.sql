@@ -0,0 +1,11 @@
+-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (actor_id, user_id, action, method, path, status, ip, request_id, details)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListAuditLogEntries :many
+SELECT * FROM audit_log
+WHERE
+    (sqlc.arg(actor_id)::int = 0 OR actor_id = sqlc.arg(actor_id)) AND
+    (sqlc.arg(user_id)::int = 0 OR user_id = sqlc.arg(user_id))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
@@ -0,0 +1,57 @@
+-- name: FindInterestsBySynonyms :many
+SELECT interest_synonyms.synonym, interests.id, interests.slug
+FROM interest_synonyms
+JOIN interests ON interests.id = interest_synonyms.interest_id
+WHERE interest_synonyms.synonym = ANY(sqlc.arg(synonyms)::text[]);
+
+-- name: CreateInterest :one
+-- Если интерес с таким slug уже есть, возвращает его
+INSERT INTO interests (slug)
+VALUES ($1)
+ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+RETURNING *;
+
+-- name: AddInterestLabel :exec
+INSERT INTO interest_labels (interest_id, locale, label)
+VALUES ($1, $2, $3)
+ON CONFLICT (interest_id, locale) DO NOTHING;
+
+-- name: AddInterestSynonym :exec
+INSERT INTO interest_synonyms (synonym, interest_id)
+VALUES ($1, $2)
+ON CONFLICT (synonym) DO NOTHING;
+
+-- name: SuggestInterests :many
+-- Интересы, одно из написаний которых начинается с prefix, от самых популярных.
+-- Название берется на языке locale, а если его нет - на английском
+SELECT interests.id, interests.slug,
+    COALESCE(localized.label, english.label, interests.slug)::text AS label,
+    (SELECT COUNT(*) FROM profile_interests WHERE profile_interests.interest_id = interests.id) AS profiles_count
+FROM interests
+LEFT JOIN interest_labels AS localized ON localized.interest_id = interests.id AND localized.locale = sqlc.arg(locale)
+LEFT JOIN interest_labels AS english ON english.interest_id = interests.id AND english.locale = 'en'
+WHERE interests.id IN (
+        SELECT interest_id FROM interest_synonyms
+        WHERE synonym LIKE sqlc.arg(prefix)::text || '%'
+    )
+ORDER BY profiles_count DESC, label
+LIMIT sqlc.arg(limit_count);
+
+-- name: DeleteProfileInterests :exec
+DELETE FROM profile_interests
+WHERE profile_id = $1;
+
+-- name: AddProfileInterests :exec
+-- Привязывает к анкете интересы по slug в порядке перечисления
+INSERT INTO profile_interests (profile_id, interest_id, position)
+SELECT sqlc.arg(profile_id)::int, interests.id, items.position
+FROM unnest(sqlc.arg(slugs)::text[]) WITH ORDINALITY AS items(slug, position)
+JOIN interests ON interests.slug = items.slug
+ON CONFLICT (profile_id, interest_id) DO NOTHING;
+
+-- name: ListProfileInterests :many
+SELECT profile_interests.profile_id, interests.slug
+FROM profile_interests
+JOIN interests ON interests.id = profile_interests.interest_id
+WHERE profile_interests.profile_id = ANY(sqlc.arg(profile_ids)::int[])
+ORDER BY profile_interests.profile_id, profile_interests.position;
@@ -0,0 +1,32 @@
+-- name: GetLoginAttempts :one
+SELECT * FROM login_attempts
+WHERE key = $1;
+
+-- name: InsertLoginAttempts :execrows
+INSERT INTO login_attempts (key, failures, last_failure_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (key) DO NOTHING;
+
+-- name: SwapLoginAttempts :execrows
+UPDATE login_attempts
+SET failures = sqlc.arg(failures), last_failure_at = sqlc.arg(last_failure_at)
+WHERE key = sqlc.arg(key)
+    AND failures = sqlc.arg(seen_failures)
+    AND last_failure_at = sqlc.arg(seen_last_failure_at);
+
+-- name: ReleaseLoginAttempt :exec
+UPDATE login_attempts
+SET failures = failures - 1,
+    last_failure_at = CASE
+        WHEN last_failure_at = sqlc.arg(reserved_at)::timestamptz THEN sqlc.arg(previous_failure_at)::timestamptz
+        ELSE last_failure_at
+    END
+WHERE key = sqlc.arg(key) AND failures > 0;
+
+-- name: DeleteLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE key = $1;
+
+-- name: DeleteStaleLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE last_failure_at < $1;
@@ -0,0 +1,68 @@
+-- name: CreateProfilePhoto :one
+-- Новая фотография добавляется в конец списка; первая фотография анкеты становится основной
+INSERT INTO profile_photos (profile_id, storage_key, content_type, size_bytes, width, height, position, is_primary)
+VALUES (
+    sqlc.arg(profile_id), sqlc.arg(storage_key), sqlc.arg(content_type), sqlc.arg(size_bytes), sqlc.arg(width), sqlc.arg(height),
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM profile_photos WHERE profile_id = sqlc.arg(profile_id)),
+    NOT EXISTS (SELECT 1 FROM profile_photos WHERE profile_id = sqlc.arg(profile_id) AND is_primary)
+)
+RETURNING *;
+
+-- name: ListProfilePhotos :many
+SELECT * FROM profile_photos
+WHERE profile_id = $1
+ORDER BY position, id;
+
+-- name: ListPhotosByProfiles :many
+SELECT * FROM profile_photos
+WHERE profile_id = ANY(sqlc.arg(profile_ids)::int[])
+ORDER BY profile_id, position, id;
+
+-- name: CountProfilePhotos :one
+SELECT COUNT(*) FROM profile_photos
+WHERE profile_id = $1;
+
+-- name: SetProfilePhotoPosition :execrows
+UPDATE profile_photos
+SET position = $3
+WHERE id = $1 AND profile_id = $2;
+
+-- name: ClearPrimaryProfilePhoto :exec
+UPDATE profile_photos
+SET is_primary = FALSE
+WHERE profile_id = $1 AND is_primary;
+
+-- name: SetPrimaryProfilePhoto :execrows
+UPDATE profile_photos
+SET is_primary = TRUE
+WHERE id = $1 AND profile_id = $2;
+
+-- name: PromoteFirstProfilePhoto :exec
+-- Делает основной первую фотографию, если у анкеты не осталось основной
+UPDATE profile_photos
+SET is_primary = TRUE
+WHERE id = (
+        SELECT id FROM profile_photos AS candidate
+        WHERE candidate.profile_id = sqlc.arg(profile_id)
+        ORDER BY candidate.position, candidate.id
+        LIMIT 1
+    ) AND NOT EXISTS (
+        SELECT 1 FROM profile_photos AS existing
+        WHERE existing.profile_id = sqlc.arg(profile_id) AND existing.is_primary
+    );
+
+-- name: DetachProfilePhoto :one
+UPDATE profile_photos
+SET profile_id = NULL, is_primary = FALSE
+WHERE id = $1 AND profile_id = $2
+RETURNING *;
+
+-- name: ListOrphanedProfilePhotos :many
+SELECT * FROM profile_photos
+WHERE profile_id IS NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: DeleteProfilePhoto :exec
+DELETE FROM profile_photos
+WHERE id = $1 AND profile_id IS NULL;
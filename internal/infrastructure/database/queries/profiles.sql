@@ -1,10 +1,17 @@
 -- name: CreateProfile :one
-INSERT INTO profiles (user_id, first_name, last_name, age, gender, city, interests)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO profiles (user_id, first_name, last_name, birth_date, gender, city)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetProfileByID :one
 SELECT * FROM profiles
+WHERE id = $1 AND hidden_at IS NULL AND NOT EXISTS (
+        SELECT 1 FROM users
+        WHERE users.id = profiles.user_id AND users.deleted_at IS NOT NULL
+    );
+
+-- name: GetProfileByIDIncludingHidden :one
+SELECT * FROM profiles
 WHERE id = $1;
 
 -- name: GetProfileByUserID :one
@@ -12,23 +19,67 @@ SELECT * FROM profiles
 WHERE user_id = $1;
 
 -- name: UpdateProfile :one
-UPDATE profiles 
-SET first_name = $2, last_name = $3, age = $4, gender = $5, city = $6, interests = $7, updated_at = CURRENT_TIMESTAMP
-WHERE user_id = $1
+UPDATE profiles
+SET first_name = sqlc.arg(first_name), last_name = sqlc.arg(last_name), birth_date = sqlc.narg(birth_date), gender = sqlc.narg(gender),
+    city = sqlc.narg(city), version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id) AND (sqlc.arg(expected_version)::int = 0 OR version = sqlc.arg(expected_version))
+RETURNING *;
+
+-- name: PatchProfile :one
+UPDATE profiles
+SET first_name = CASE WHEN sqlc.arg(set_first_name)::bool THEN sqlc.arg(first_name)::text ELSE first_name END,
+    last_name = CASE WHEN sqlc.arg(set_last_name)::bool THEN sqlc.arg(last_name)::text ELSE last_name END,
+    birth_date = CASE WHEN sqlc.arg(set_birth_date)::bool THEN sqlc.narg(birth_date)::date ELSE birth_date END,
+    gender = CASE WHEN sqlc.arg(set_gender)::bool THEN sqlc.narg(gender)::text ELSE gender END,
+    city = CASE WHEN sqlc.arg(set_city)::bool THEN sqlc.narg(city)::text ELSE city END,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id) AND (sqlc.arg(expected_version)::int = 0 OR version = sqlc.arg(expected_version))
 RETURNING *;
 
--- name: SearchProfiles :many
+-- name: BumpProfileVersion :exec
+UPDATE profiles
+SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListProfilesByIDs :many
+-- Анкеты, найденные поиском; порядок задает вызывающий
 SELECT * FROM profiles
-WHERE 
-    ($1::text IS NULL OR gender = $1) AND
-    ($2::text IS NULL OR city = $2) AND
-    ($3::text[] IS NULL OR interests && $3)
-ORDER BY created_at DESC
-LIMIT $4 OFFSET $5;
-
--- name: GetProfilesCount :one
-SELECT COUNT(*) FROM profiles
-WHERE 
-    ($1::text IS NULL OR gender = $1) AND
-    ($2::text IS NULL OR city = $2) AND
-    ($3::text[] IS NULL OR interests && $3); 
\ No newline at end of file
+WHERE id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: UpdateProfilePrivacy :one
+UPDATE profiles
+SET profile_visibility = sqlc.arg(profile_visibility), age_visibility = sqlc.arg(age_visibility),
+    birthday_visibility = sqlc.arg(birthday_visibility), zodiac_visibility = sqlc.arg(zodiac_visibility),
+    gender_visibility = sqlc.arg(gender_visibility), city_visibility = sqlc.arg(city_visibility),
+    interests_visibility = sqlc.arg(interests_visibility), photos_visibility = sqlc.arg(photos_visibility),
+    version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: SetProfileHidden :one
+UPDATE profiles
+SET hidden_at = CASE WHEN sqlc.arg(hidden)::bool THEN COALESCE(hidden_at, CURRENT_TIMESTAMP) END,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListProfileSearchMatches :many
+-- Релевантность анкет запросу q и совпадения в полях, видимых зрителю, выделенные символами U+0001 и U+0002
+SELECT id AS profile_id,
+    profile_search_score(id, user_id, city_visibility, interests_visibility,
+        sqlc.arg(viewer_id)::int, sqlc.arg(friend_ids)::int[], sqlc.arg(q)::text)::float8 AS score,
+    profile_search_highlight(first_name || ' ' || last_name, sqlc.arg(q)::text) AS names,
+    CASE WHEN profile_field_visible(city_visibility, user_id, sqlc.arg(viewer_id)::int, sqlc.arg(friend_ids)::int[])
+        THEN profile_search_highlight(city, sqlc.arg(q)::text)
+    END AS city,
+    CASE WHEN profile_field_visible(interests_visibility, user_id, sqlc.arg(viewer_id)::int, sqlc.arg(friend_ids)::int[]) THEN (
+        SELECT string_agg(profile_search_highlight(interest_labels.label, sqlc.arg(q)::text), ', '
+            ORDER BY profile_interests.position, interest_labels.locale)
+        FROM profile_interests
+        JOIN interest_labels ON interest_labels.interest_id = profile_interests.interest_id
+        WHERE profile_interests.profile_id = profiles.id
+    ) END AS interests
+FROM profiles
+WHERE id = ANY(sqlc.arg(profile_ids)::int[]);
@@ -0,0 +1,24 @@
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (token_id, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (token_id) DO NOTHING;
+
+-- name: RevokeUserTokens :exec
+INSERT INTO user_token_revocations (user_id, revoked_before)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET revoked_before = EXCLUDED.revoked_before;
+
+-- name: IsTokenRevoked :one
+SELECT (
+    EXISTS (
+        SELECT 1 FROM revoked_tokens
+        WHERE token_id = ANY(sqlc.arg(token_ids)::text[]) AND expires_at > CURRENT_TIMESTAMP
+    ) OR EXISTS (
+        SELECT 1 FROM user_token_revocations
+        WHERE user_id = sqlc.arg(user_id) AND revoked_before > sqlc.arg(issued_at)
+    )
+)::bool AS revoked;
+
+-- name: DeleteExpiredRevokedTokens :exec
+DELETE FROM revoked_tokens
+WHERE expires_at < sqlc.arg(before);
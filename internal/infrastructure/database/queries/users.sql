@@ -5,8 +5,49 @@ RETURNING *;
 
 -- name: GetUserByEmail :one
 SELECT * FROM users 
-WHERE email = $1;
+WHERE email = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByID :one
 SELECT * FROM users 
-WHERE id = $1; 
\ No newline at end of file
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateUser :one
+UPDATE users
+SET email = $2, password_hash = $3, email_verified_at = $4, updated_at = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND email = $2;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at < $1;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL;
+
+-- name: SetUserSuspended :one
+UPDATE users
+SET suspended_at = CASE WHEN sqlc.arg(suspended)::bool THEN COALESCE(suspended_at, CURRENT_TIMESTAMP) END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
@@ -9,23 +9,199 @@ import (
 	"time"
 )
 
+type ApiKey struct {
+	ID         int32        `db:"id" json:"id"`
+	UserID     int32        `db:"user_id" json:"user_id"`
+	Name       string       `db:"name" json:"name"`
+	Prefix     string       `db:"prefix" json:"prefix"`
+	KeyHash    string       `db:"key_hash" json:"key_hash"`
+	Scopes     []string     `db:"scopes" json:"scopes"`
+	ExpiresAt  sql.NullTime `db:"expires_at" json:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+}
+
+type AuditLog struct {
+	ID        int64     `db:"id" json:"id"`
+	ActorID   int32     `db:"actor_id" json:"actor_id"`
+	UserID    int32     `db:"user_id" json:"user_id"`
+	Action    string    `db:"action" json:"action"`
+	Method    string    `db:"method" json:"method"`
+	Path      string    `db:"path" json:"path"`
+	Status    int32     `db:"status" json:"status"`
+	IP        string    `db:"ip" json:"ip"`
+	RequestID string    `db:"request_id" json:"request_id"`
+	Details   string    `db:"details" json:"details"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type EmailVerificationToken struct {
+	ID        int32        `db:"id" json:"id"`
+	UserID    int32        `db:"user_id" json:"user_id"`
+	Email     string       `db:"email" json:"email"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
+type Interest struct {
+	ID        int32     `db:"id" json:"id"`
+	Slug      string    `db:"slug" json:"slug"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type InterestLabel struct {
+	InterestID int32  `db:"interest_id" json:"interest_id"`
+	Locale     string `db:"locale" json:"locale"`
+	Label      string `db:"label" json:"label"`
+}
+
+type InterestSynonym struct {
+	Synonym    string `db:"synonym" json:"synonym"`
+	InterestID int32  `db:"interest_id" json:"interest_id"`
+}
+
+type LoginAttempt struct {
+	Key           string    `db:"key" json:"key"`
+	Failures      int32     `db:"failures" json:"failures"`
+	LastFailureAt time.Time `db:"last_failure_at" json:"last_failure_at"`
+}
+
+type OauthState struct {
+	StateHash    string    `db:"state_hash" json:"state_hash"`
+	Provider     string    `db:"provider" json:"provider"`
+	CodeVerifier string    `db:"code_verifier" json:"code_verifier"`
+	Nonce        string    `db:"nonce" json:"nonce"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int32        `db:"id" json:"id"`
+	UserID    int32        `db:"user_id" json:"user_id"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
 type Profile struct {
-	ID        int32          `db:"id" json:"id"`
-	UserID    int32          `db:"user_id" json:"user_id"`
-	FirstName string         `db:"first_name" json:"first_name"`
-	LastName  string         `db:"last_name" json:"last_name"`
-	Age       sql.NullInt32  `db:"age" json:"age"`
-	Gender    sql.NullString `db:"gender" json:"gender"`
-	City      sql.NullString `db:"city" json:"city"`
-	Interests []string       `db:"interests" json:"interests"`
-	CreatedAt time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	ID                  int32          `db:"id" json:"id"`
+	UserID              int32          `db:"user_id" json:"user_id"`
+	FirstName           string         `db:"first_name" json:"first_name"`
+	LastName            string         `db:"last_name" json:"last_name"`
+	Gender              sql.NullString `db:"gender" json:"gender"`
+	City                sql.NullString `db:"city" json:"city"`
+	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at" json:"updated_at"`
+	HiddenAt            sql.NullTime   `db:"hidden_at" json:"hidden_at"`
+	Version             int32          `db:"version" json:"version"`
+	ProfileVisibility   string         `db:"profile_visibility" json:"profile_visibility"`
+	AgeVisibility       string         `db:"age_visibility" json:"age_visibility"`
+	GenderVisibility    string         `db:"gender_visibility" json:"gender_visibility"`
+	CityVisibility      string         `db:"city_visibility" json:"city_visibility"`
+	InterestsVisibility string         `db:"interests_visibility" json:"interests_visibility"`
+	PhotosVisibility    string         `db:"photos_visibility" json:"photos_visibility"`
+	BirthDate           sql.NullTime   `db:"birth_date" json:"birth_date"`
+	BirthdayVisibility  string         `db:"birthday_visibility" json:"birthday_visibility"`
+	ZodiacVisibility    string         `db:"zodiac_visibility" json:"zodiac_visibility"`
+}
+
+type ProfileInterest struct {
+	ProfileID  int32 `db:"profile_id" json:"profile_id"`
+	InterestID int32 `db:"interest_id" json:"interest_id"`
+	Position   int32 `db:"position" json:"position"`
+}
+
+type ProfilePhoto struct {
+	ID          int32         `db:"id" json:"id"`
+	ProfileID   sql.NullInt32 `db:"profile_id" json:"profile_id"`
+	StorageKey  string        `db:"storage_key" json:"storage_key"`
+	ContentType string        `db:"content_type" json:"content_type"`
+	SizeBytes   int64         `db:"size_bytes" json:"size_bytes"`
+	Width       int32         `db:"width" json:"width"`
+	Height      int32         `db:"height" json:"height"`
+	Position    int32         `db:"position" json:"position"`
+	IsPrimary   bool          `db:"is_primary" json:"is_primary"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+}
+
+type ProfileSearchDocument struct {
+	ProfileID int32       `db:"profile_id" json:"profile_id"`
+	Document  interface{} `db:"document" json:"document"`
+	Names     string      `db:"names" json:"names"`
+	City      string      `db:"city" json:"city"`
+}
+
+type RefreshToken struct {
+	ID        int32        `db:"id" json:"id"`
+	UserID    int32        `db:"user_id" json:"user_id"`
+	FamilyID  string       `db:"family_id" json:"family_id"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
+type RevokedToken struct {
+	TokenID   string    `db:"token_id" json:"token_id"`
+	UserID    int32     `db:"user_id" json:"user_id"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Session struct {
+	ID         string       `db:"id" json:"id"`
+	UserID     int32        `db:"user_id" json:"user_id"`
+	Device     string       `db:"device" json:"device"`
+	UserAgent  string       `db:"user_agent" json:"user_agent"`
+	IP         string       `db:"ip" json:"ip"`
+	ExpiresAt  time.Time    `db:"expires_at" json:"expires_at"`
+	LastSeenAt time.Time    `db:"last_seen_at" json:"last_seen_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
 }
 
 type User struct {
-	ID           int32     `db:"id" json:"id"`
-	Email        string    `db:"email" json:"email"`
-	PasswordHash string    `db:"password_hash" json:"password_hash"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID              int32        `db:"id" json:"id"`
+	Email           string       `db:"email" json:"email"`
+	PasswordHash    string       `db:"password_hash" json:"password_hash"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	EmailVerifiedAt sql.NullTime `db:"email_verified_at" json:"email_verified_at"`
+	DeletedAt       sql.NullTime `db:"deleted_at" json:"deleted_at"`
+	Roles           []string     `db:"roles" json:"roles"`
+	SuspendedAt     sql.NullTime `db:"suspended_at" json:"suspended_at"`
+}
+
+type UserIdentity struct {
+	ID        int32     `db:"id" json:"id"`
+	UserID    int32     `db:"user_id" json:"user_id"`
+	Provider  string    `db:"provider" json:"provider"`
+	Subject   string    `db:"subject" json:"subject"`
+	Email     string    `db:"email" json:"email"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type UserRecoveryCode struct {
+	ID        int32        `db:"id" json:"id"`
+	UserID    int32        `db:"user_id" json:"user_id"`
+	CodeHash  string       `db:"code_hash" json:"code_hash"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
+type UserTokenRevocation struct {
+	UserID        int32     `db:"user_id" json:"user_id"`
+	RevokedBefore time.Time `db:"revoked_before" json:"revoked_before"`
+}
+
+type UserTotp struct {
+	UserID       int32        `db:"user_id" json:"user_id"`
+	Secret       string       `db:"secret" json:"secret"`
+	ConfirmedAt  sql.NullTime `db:"confirmed_at" json:"confirmed_at"`
+	LastUsedStep int64        `db:"last_used_step" json:"last_used_step"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
 }
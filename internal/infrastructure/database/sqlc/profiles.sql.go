@@ -12,20 +12,30 @@ import (
 	"github.com/lib/pq"
 )
 
+const bumpProfileVersion = `-- name: BumpProfileVersion :exec
+UPDATE profiles
+SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) BumpProfileVersion(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, bumpProfileVersion, id)
+	return err
+}
+
 const createProfile = `-- name: CreateProfile :one
-INSERT INTO profiles (user_id, first_name, last_name, age, gender, city, interests)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, user_id, first_name, last_name, age, gender, city, interests, created_at, updated_at
+INSERT INTO profiles (user_id, first_name, last_name, birth_date, gender, city)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility
 `
 
 type CreateProfileParams struct {
 	UserID    int32          `db:"user_id" json:"user_id"`
 	FirstName string         `db:"first_name" json:"first_name"`
 	LastName  string         `db:"last_name" json:"last_name"`
-	Age       sql.NullInt32  `db:"age" json:"age"`
+	BirthDate sql.NullTime   `db:"birth_date" json:"birth_date"`
 	Gender    sql.NullString `db:"gender" json:"gender"`
 	City      sql.NullString `db:"city" json:"city"`
-	Interests []string       `db:"interests" json:"interests"`
 }
 
 func (q *Queries) CreateProfile(ctx context.Context, arg CreateProfileParams) (Profile, error) {
@@ -33,10 +43,9 @@ func (q *Queries) CreateProfile(ctx context.Context, arg CreateProfileParams) (P
 		arg.UserID,
 		arg.FirstName,
 		arg.LastName,
-		arg.Age,
+		arg.BirthDate,
 		arg.Gender,
 		arg.City,
-		pq.Array(arg.Interests),
 	)
 	var i Profile
 	err := row.Scan(
@@ -44,19 +53,31 @@ func (q *Queries) CreateProfile(ctx context.Context, arg CreateProfileParams) (P
 		&i.UserID,
 		&i.FirstName,
 		&i.LastName,
-		&i.Age,
 		&i.Gender,
 		&i.City,
-		pq.Array(&i.Interests),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
 	)
 	return i, err
 }
 
 const getProfileByID = `-- name: GetProfileByID :one
-SELECT id, user_id, first_name, last_name, age, gender, city, interests, created_at, updated_at FROM profiles
-WHERE id = $1
+SELECT id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility FROM profiles
+WHERE id = $1 AND hidden_at IS NULL AND NOT EXISTS (
+        SELECT 1 FROM users
+        WHERE users.id = profiles.user_id AND users.deleted_at IS NOT NULL
+    )
 `
 
 func (q *Queries) GetProfileByID(ctx context.Context, id int32) (Profile, error) {
@@ -67,18 +88,59 @@ func (q *Queries) GetProfileByID(ctx context.Context, id int32) (Profile, error)
 		&i.UserID,
 		&i.FirstName,
 		&i.LastName,
-		&i.Age,
 		&i.Gender,
 		&i.City,
-		pq.Array(&i.Interests),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
+	)
+	return i, err
+}
+
+const getProfileByIDIncludingHidden = `-- name: GetProfileByIDIncludingHidden :one
+SELECT id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility FROM profiles
+WHERE id = $1
+`
+
+func (q *Queries) GetProfileByIDIncludingHidden(ctx context.Context, id int32) (Profile, error) {
+	row := q.db.QueryRowContext(ctx, getProfileByIDIncludingHidden, id)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Gender,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
 	)
 	return i, err
 }
 
 const getProfileByUserID = `-- name: GetProfileByUserID :one
-SELECT id, user_id, first_name, last_name, age, gender, city, interests, created_at, updated_at FROM profiles
+SELECT id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility FROM profiles
 WHERE user_id = $1
 `
 
@@ -90,63 +152,102 @@ func (q *Queries) GetProfileByUserID(ctx context.Context, userID int32) (Profile
 		&i.UserID,
 		&i.FirstName,
 		&i.LastName,
-		&i.Age,
 		&i.Gender,
 		&i.City,
-		pq.Array(&i.Interests),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
 	)
 	return i, err
 }
 
-const getProfilesCount = `-- name: GetProfilesCount :one
-SELECT COUNT(*) FROM profiles
-WHERE 
-    ($1::text IS NULL OR gender = $1) AND
-    ($2::text IS NULL OR city = $2) AND
-    ($3::text[] IS NULL OR interests && $3)
+const listProfileSearchMatches = `-- name: ListProfileSearchMatches :many
+SELECT id AS profile_id,
+    profile_search_score(id, user_id, city_visibility, interests_visibility,
+        $1::int, $2::int[], $3::text)::float8 AS score,
+    profile_search_highlight(first_name || ' ' || last_name, $3::text) AS names,
+    CASE WHEN profile_field_visible(city_visibility, user_id, $1::int, $2::int[])
+        THEN profile_search_highlight(city, $3::text)
+    END AS city,
+    CASE WHEN profile_field_visible(interests_visibility, user_id, $1::int, $2::int[]) THEN (
+        SELECT string_agg(profile_search_highlight(interest_labels.label, $3::text), ', '
+            ORDER BY profile_interests.position, interest_labels.locale)
+        FROM profile_interests
+        JOIN interest_labels ON interest_labels.interest_id = profile_interests.interest_id
+        WHERE profile_interests.profile_id = profiles.id
+    ) END AS interests
+FROM profiles
+WHERE id = ANY($4::int[])
 `
 
-type GetProfilesCountParams struct {
-	Column1 string   `db:"column_1" json:"column_1"`
-	Column2 string   `db:"column_2" json:"column_2"`
-	Column3 []string `db:"column_3" json:"column_3"`
+type ListProfileSearchMatchesParams struct {
+	ViewerID   int32   `db:"viewer_id" json:"viewer_id"`
+	FriendIds  []int32 `db:"friend_ids" json:"friend_ids"`
+	Q          string  `db:"q" json:"q"`
+	ProfileIds []int32 `db:"profile_ids" json:"profile_ids"`
 }
 
-func (q *Queries) GetProfilesCount(ctx context.Context, arg GetProfilesCountParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getProfilesCount, arg.Column1, arg.Column2, pq.Array(arg.Column3))
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type ListProfileSearchMatchesRow struct {
+	ProfileID int32          `db:"profile_id" json:"profile_id"`
+	Score     float64        `db:"score" json:"score"`
+	Names     sql.NullString `db:"names" json:"names"`
+	City      sql.NullString `db:"city" json:"city"`
+	Interests sql.NullString `db:"interests" json:"interests"`
 }
 
-const searchProfiles = `-- name: SearchProfiles :many
-SELECT id, user_id, first_name, last_name, age, gender, city, interests, created_at, updated_at FROM profiles
-WHERE 
-    ($1::text IS NULL OR gender = $1) AND
-    ($2::text IS NULL OR city = $2) AND
-    ($3::text[] IS NULL OR interests && $3)
-ORDER BY created_at DESC
-LIMIT $4 OFFSET $5
+// Релевантность анкет запросу q и совпадения в полях, видимых зрителю, выделенные символами U+0001 и U+0002
+func (q *Queries) ListProfileSearchMatches(ctx context.Context, arg ListProfileSearchMatchesParams) ([]ListProfileSearchMatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProfileSearchMatches,
+		arg.ViewerID,
+		pq.Array(arg.FriendIds),
+		arg.Q,
+		pq.Array(arg.ProfileIds),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProfileSearchMatchesRow{}
+	for rows.Next() {
+		var i ListProfileSearchMatchesRow
+		if err := rows.Scan(
+			&i.ProfileID,
+			&i.Score,
+			&i.Names,
+			&i.City,
+			&i.Interests,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProfilesByIDs = `-- name: ListProfilesByIDs :many
+SELECT id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility FROM profiles
+WHERE id = ANY($1::int[])
 `
 
-type SearchProfilesParams struct {
-	Column1 string   `db:"column_1" json:"column_1"`
-	Column2 string   `db:"column_2" json:"column_2"`
-	Column3 []string `db:"column_3" json:"column_3"`
-	Limit   int32    `db:"limit" json:"limit"`
-	Offset  int32    `db:"offset" json:"offset"`
-}
-
-func (q *Queries) SearchProfiles(ctx context.Context, arg SearchProfilesParams) ([]Profile, error) {
-	rows, err := q.db.QueryContext(ctx, searchProfiles,
-		arg.Column1,
-		arg.Column2,
-		pq.Array(arg.Column3),
-		arg.Limit,
-		arg.Offset,
-	)
+// Анкеты, найденные поиском; порядок задает вызывающий
+func (q *Queries) ListProfilesByIDs(ctx context.Context, ids []int32) ([]Profile, error) {
+	rows, err := q.db.QueryContext(ctx, listProfilesByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
@@ -159,12 +260,21 @@ func (q *Queries) SearchProfiles(ctx context.Context, arg SearchProfilesParams)
 			&i.UserID,
 			&i.FirstName,
 			&i.LastName,
-			&i.Age,
 			&i.Gender,
 			&i.City,
-			pq.Array(&i.Interests),
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.HiddenAt,
+			&i.Version,
+			&i.ProfileVisibility,
+			&i.AgeVisibility,
+			&i.GenderVisibility,
+			&i.CityVisibility,
+			&i.InterestsVisibility,
+			&i.PhotosVisibility,
+			&i.BirthDate,
+			&i.BirthdayVisibility,
+			&i.ZodiacVisibility,
 		); err != nil {
 			return nil, err
 		}
@@ -179,32 +289,202 @@ func (q *Queries) SearchProfiles(ctx context.Context, arg SearchProfilesParams)
 	return items, nil
 }
 
+const patchProfile = `-- name: PatchProfile :one
+UPDATE profiles
+SET first_name = CASE WHEN $1::bool THEN $2::text ELSE first_name END,
+    last_name = CASE WHEN $3::bool THEN $4::text ELSE last_name END,
+    birth_date = CASE WHEN $5::bool THEN $6::date ELSE birth_date END,
+    gender = CASE WHEN $7::bool THEN $8::text ELSE gender END,
+    city = CASE WHEN $9::bool THEN $10::text ELSE city END,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $11 AND ($12::int = 0 OR version = $12)
+RETURNING id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility
+`
+
+type PatchProfileParams struct {
+	SetFirstName    bool           `db:"set_first_name" json:"set_first_name"`
+	FirstName       string         `db:"first_name" json:"first_name"`
+	SetLastName     bool           `db:"set_last_name" json:"set_last_name"`
+	LastName        string         `db:"last_name" json:"last_name"`
+	SetBirthDate    bool           `db:"set_birth_date" json:"set_birth_date"`
+	BirthDate       sql.NullTime   `db:"birth_date" json:"birth_date"`
+	SetGender       bool           `db:"set_gender" json:"set_gender"`
+	Gender          sql.NullString `db:"gender" json:"gender"`
+	SetCity         bool           `db:"set_city" json:"set_city"`
+	City            sql.NullString `db:"city" json:"city"`
+	UserID          int32          `db:"user_id" json:"user_id"`
+	ExpectedVersion int32          `db:"expected_version" json:"expected_version"`
+}
+
+func (q *Queries) PatchProfile(ctx context.Context, arg PatchProfileParams) (Profile, error) {
+	row := q.db.QueryRowContext(ctx, patchProfile,
+		arg.SetFirstName,
+		arg.FirstName,
+		arg.SetLastName,
+		arg.LastName,
+		arg.SetBirthDate,
+		arg.BirthDate,
+		arg.SetGender,
+		arg.Gender,
+		arg.SetCity,
+		arg.City,
+		arg.UserID,
+		arg.ExpectedVersion,
+	)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Gender,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
+	)
+	return i, err
+}
+
+const setProfileHidden = `-- name: SetProfileHidden :one
+UPDATE profiles
+SET hidden_at = CASE WHEN $1::bool THEN COALESCE(hidden_at, CURRENT_TIMESTAMP) END,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+RETURNING id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility
+`
+
+type SetProfileHiddenParams struct {
+	Hidden bool  `db:"hidden" json:"hidden"`
+	ID     int32 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetProfileHidden(ctx context.Context, arg SetProfileHiddenParams) (Profile, error) {
+	row := q.db.QueryRowContext(ctx, setProfileHidden, arg.Hidden, arg.ID)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Gender,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
+	)
+	return i, err
+}
+
 const updateProfile = `-- name: UpdateProfile :one
-UPDATE profiles 
-SET first_name = $2, last_name = $3, age = $4, gender = $5, city = $6, interests = $7, updated_at = CURRENT_TIMESTAMP
-WHERE user_id = $1
-RETURNING id, user_id, first_name, last_name, age, gender, city, interests, created_at, updated_at
+UPDATE profiles
+SET first_name = $1, last_name = $2, birth_date = $3, gender = $4,
+    city = $5, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $6 AND ($7::int = 0 OR version = $7)
+RETURNING id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility
 `
 
 type UpdateProfileParams struct {
-	UserID    int32          `db:"user_id" json:"user_id"`
-	FirstName string         `db:"first_name" json:"first_name"`
-	LastName  string         `db:"last_name" json:"last_name"`
-	Age       sql.NullInt32  `db:"age" json:"age"`
-	Gender    sql.NullString `db:"gender" json:"gender"`
-	City      sql.NullString `db:"city" json:"city"`
-	Interests []string       `db:"interests" json:"interests"`
+	FirstName       string         `db:"first_name" json:"first_name"`
+	LastName        string         `db:"last_name" json:"last_name"`
+	BirthDate       sql.NullTime   `db:"birth_date" json:"birth_date"`
+	Gender          sql.NullString `db:"gender" json:"gender"`
+	City            sql.NullString `db:"city" json:"city"`
+	UserID          int32          `db:"user_id" json:"user_id"`
+	ExpectedVersion int32          `db:"expected_version" json:"expected_version"`
 }
 
 func (q *Queries) UpdateProfile(ctx context.Context, arg UpdateProfileParams) (Profile, error) {
 	row := q.db.QueryRowContext(ctx, updateProfile,
-		arg.UserID,
 		arg.FirstName,
 		arg.LastName,
-		arg.Age,
+		arg.BirthDate,
 		arg.Gender,
 		arg.City,
-		pq.Array(arg.Interests),
+		arg.UserID,
+		arg.ExpectedVersion,
+	)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Gender,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
+	)
+	return i, err
+}
+
+const updateProfilePrivacy = `-- name: UpdateProfilePrivacy :one
+UPDATE profiles
+SET profile_visibility = $1, age_visibility = $2,
+    birthday_visibility = $3, zodiac_visibility = $4,
+    gender_visibility = $5, city_visibility = $6,
+    interests_visibility = $7, photos_visibility = $8,
+    version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $9
+RETURNING id, user_id, first_name, last_name, gender, city, created_at, updated_at, hidden_at, version, profile_visibility, age_visibility, gender_visibility, city_visibility, interests_visibility, photos_visibility, birth_date, birthday_visibility, zodiac_visibility
+`
+
+type UpdateProfilePrivacyParams struct {
+	ProfileVisibility   string `db:"profile_visibility" json:"profile_visibility"`
+	AgeVisibility       string `db:"age_visibility" json:"age_visibility"`
+	BirthdayVisibility  string `db:"birthday_visibility" json:"birthday_visibility"`
+	ZodiacVisibility    string `db:"zodiac_visibility" json:"zodiac_visibility"`
+	GenderVisibility    string `db:"gender_visibility" json:"gender_visibility"`
+	CityVisibility      string `db:"city_visibility" json:"city_visibility"`
+	InterestsVisibility string `db:"interests_visibility" json:"interests_visibility"`
+	PhotosVisibility    string `db:"photos_visibility" json:"photos_visibility"`
+	UserID              int32  `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) UpdateProfilePrivacy(ctx context.Context, arg UpdateProfilePrivacyParams) (Profile, error) {
+	row := q.db.QueryRowContext(ctx, updateProfilePrivacy,
+		arg.ProfileVisibility,
+		arg.AgeVisibility,
+		arg.BirthdayVisibility,
+		arg.ZodiacVisibility,
+		arg.GenderVisibility,
+		arg.CityVisibility,
+		arg.InterestsVisibility,
+		arg.PhotosVisibility,
+		arg.UserID,
 	)
 	var i Profile
 	err := row.Scan(
@@ -212,12 +492,21 @@ func (q *Queries) UpdateProfile(ctx context.Context, arg UpdateProfileParams) (P
 		&i.UserID,
 		&i.FirstName,
 		&i.LastName,
-		&i.Age,
 		&i.Gender,
 		&i.City,
-		pq.Array(&i.Interests),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.Version,
+		&i.ProfileVisibility,
+		&i.AgeVisibility,
+		&i.GenderVisibility,
+		&i.CityVisibility,
+		&i.InterestsVisibility,
+		&i.PhotosVisibility,
+		&i.BirthDate,
+		&i.BirthdayVisibility,
+		&i.ZodiacVisibility,
 	)
 	return i, err
 }
@@ -6,18 +6,103 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddInterestLabel(ctx context.Context, arg AddInterestLabelParams) error
+	AddInterestSynonym(ctx context.Context, arg AddInterestSynonymParams) error
+	AddProfileInterests(ctx context.Context, arg AddProfileInterestsParams) error
+	BumpProfileVersion(ctx context.Context, id int32) error
+	ClearPrimaryProfilePhoto(ctx context.Context, profileID sql.NullInt32) error
+	ConfirmUserTOTP(ctx context.Context, arg ConfirmUserTOTPParams) (int64, error)
+	ConsumeOAuthState(ctx context.Context, stateHash string) (OauthState, error)
+	CountProfilePhotos(ctx context.Context, profileID sql.NullInt32) (int64, error)
+	CountUsers(ctx context.Context) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) error
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
+	CreateInterest(ctx context.Context, slug string) (Interest, error)
+	CreateOAuthState(ctx context.Context, arg CreateOAuthStateParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
 	CreateProfile(ctx context.Context, arg CreateProfileParams) (Profile, error)
+	CreateProfilePhoto(ctx context.Context, arg CreateProfilePhotoParams) (ProfilePhoto, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	CreateUserRecoveryCode(ctx context.Context, arg CreateUserRecoveryCodeParams) error
+	DeleteExpiredOAuthStates(ctx context.Context, expiresAt time.Time) error
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) error
+	DeleteExpiredRevokedTokens(ctx context.Context, before time.Time) error
+	DeleteLoginAttempts(ctx context.Context, key string) error
+	DeleteProfileInterests(ctx context.Context, profileID int32) error
+	DeleteProfilePhoto(ctx context.Context, id int32) error
+	DeleteStaleLoginAttempts(ctx context.Context, lastFailureAt time.Time) error
+	DeleteStaleSessions(ctx context.Context, before time.Time) error
+	DeleteUser(ctx context.Context, id int32) error
+	DeleteUserRecoveryCodes(ctx context.Context, userID int32) error
+	DeleteUserTOTP(ctx context.Context, userID int32) error
+	DetachProfilePhoto(ctx context.Context, arg DetachProfilePhotoParams) (ProfilePhoto, error)
+	FindInterestsBySynonyms(ctx context.Context, synonyms []string) ([]FindInterestsBySynonymsRow, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetEmailVerificationTokenByHash(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	GetLoginAttempts(ctx context.Context, key string) (LoginAttempt, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (PasswordResetToken, error)
 	GetProfileByID(ctx context.Context, id int32) (Profile, error)
+	GetProfileByIDIncludingHidden(ctx context.Context, id int32) (Profile, error)
 	GetProfileByUserID(ctx context.Context, userID int32) (Profile, error)
-	GetProfilesCount(ctx context.Context, arg GetProfilesCountParams) (int64, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
-	SearchProfiles(ctx context.Context, arg SearchProfilesParams) ([]Profile, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	GetUserTOTP(ctx context.Context, userID int32) (UserTotp, error)
+	InsertLoginAttempts(ctx context.Context, arg InsertLoginAttemptsParams) (int64, error)
+	InvalidateUserEmailVerificationTokens(ctx context.Context, userID int32) error
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID int32) error
+	IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error)
+	ListAuditLogEntries(ctx context.Context, arg ListAuditLogEntriesParams) ([]AuditLog, error)
+	ListOrphanedProfilePhotos(ctx context.Context, limit int32) ([]ProfilePhoto, error)
+	ListPhotosByProfiles(ctx context.Context, profileIds []int32) ([]ProfilePhoto, error)
+	ListProfileInterests(ctx context.Context, profileIds []int32) ([]ListProfileInterestsRow, error)
+	ListProfilePhotos(ctx context.Context, profileID sql.NullInt32) ([]ProfilePhoto, error)
+	ListProfileSearchMatches(ctx context.Context, arg ListProfileSearchMatchesParams) ([]ListProfileSearchMatchesRow, error)
+	ListProfilesByIDs(ctx context.Context, ids []int32) ([]Profile, error)
+	ListUserAPIKeys(ctx context.Context, userID int32) ([]ApiKey, error)
+	ListUserIdentities(ctx context.Context, userID int32) ([]UserIdentity, error)
+	ListUserSessions(ctx context.Context, userID int32) ([]Session, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	MarkEmailVerificationTokenUsed(ctx context.Context, id int32) (int64, error)
+	MarkPasswordResetTokenUsed(ctx context.Context, id int32) (int64, error)
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error)
+	PatchProfile(ctx context.Context, arg PatchProfileParams) (Profile, error)
+	PromoteFirstProfilePhoto(ctx context.Context, profileID sql.NullInt32) error
+	PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	ReleaseLoginAttempt(ctx context.Context, arg ReleaseLoginAttemptParams) error
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeRefreshToken(ctx context.Context, id int32) (int64, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error)
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	RevokeUserRefreshTokens(ctx context.Context, userID int32) error
+	RevokeUserSessions(ctx context.Context, userID int32) error
+	RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error
+	SetPrimaryProfilePhoto(ctx context.Context, arg SetPrimaryProfilePhotoParams) (int64, error)
+	SetProfileHidden(ctx context.Context, arg SetProfileHiddenParams) (Profile, error)
+	SetProfilePhotoPosition(ctx context.Context, arg SetProfilePhotoPositionParams) (int64, error)
+	SetUserSuspended(ctx context.Context, arg SetUserSuspendedParams) (User, error)
+	SoftDeleteUser(ctx context.Context, id int32) (int64, error)
+	SuggestInterests(ctx context.Context, arg SuggestInterestsParams) ([]SuggestInterestsRow, error)
+	SwapLoginAttempts(ctx context.Context, arg SwapLoginAttemptsParams) (int64, error)
+	TouchAPIKeyLastUsed(ctx context.Context, id int32) error
+	TouchSession(ctx context.Context, arg TouchSessionParams) error
 	UpdateProfile(ctx context.Context, arg UpdateProfileParams) (Profile, error)
+	UpdateProfilePrivacy(ctx context.Context, arg UpdateProfilePrivacyParams) (Profile, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTotp, error)
+	UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (int64, error)
+	UseUserTOTPStep(ctx context.Context, arg UseUserTOTPStepParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
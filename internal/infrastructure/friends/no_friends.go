@@ -0,0 +1,25 @@
+package friends
+
+import (
+	"context"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+type noFriends struct{}
+
+// NewNoFriends создает заглушку, у которой ни у кого нет друзей. Используется, пока в сети
+// нет дружбы: поля с видимостью friends видит только владелец
+func NewNoFriends() services.FriendProvider {
+	return noFriends{}
+}
+
+// AreFriends всегда возвращает false
+func (noFriends) AreFriends(ctx context.Context, userID, otherUserID int) (bool, error) {
+	return false, nil
+}
+
+// FriendIDs всегда возвращает пустой список
+func (noFriends) FriendIDs(ctx context.Context, userID int) ([]int, error) {
+	return nil, nil
+}
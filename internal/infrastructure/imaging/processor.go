@@ -0,0 +1,136 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+const (
+	// maxSide и maxPixels ограничивают размеры изображения до декодирования,
+	// чтобы небольшой файл не мог занять гигабайты памяти
+	maxSide   = 10000
+	maxPixels = 25_000_000
+
+	originalJPEGQuality  = 90
+	thumbnailJPEGQuality = 85
+)
+
+// processor проверяет и перекодирует изображения стандартными средствами Go.
+// Поддерживаются JPEG и PNG; ориентация JPEG из EXIF применяется к пикселям,
+// так как метаданные при перекодировании не сохраняются
+type processor struct{}
+
+// NewProcessor создает обработчик изображений
+func NewProcessor() services.ImageProcessor {
+	return &processor{}
+}
+
+// Process проверяет изображение, перекодирует его без метаданных и создает уменьшенные копии
+func (p *processor) Process(data []byte, thumbnails []entities.PhotoThumbnail) (*services.ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, services.ErrUnsupportedImageType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, services.ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, services.ErrInvalidImage
+	}
+	if config.Width > maxSide || config.Height > maxSide || config.Width*config.Height > maxPixels {
+		return nil, services.ErrImageDimensions
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, services.ErrInvalidImage
+	}
+
+	var img *image.RGBA
+	if contentType == "image/jpeg" {
+		img = orient(toRGBA(decoded), jpegOrientation(data))
+	} else {
+		img = toRGBA(decoded)
+	}
+
+	original, err := encodeOriginal(img, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Уменьшенные копии хранятся в JPEG, прозрачные области PNG заливаются белым
+	flat := flatten(img)
+	result := &services.ProcessedImage{
+		Original:   original,
+		Thumbnails: make(map[string]services.ImageVariant, len(thumbnails)),
+	}
+	for _, thumbnail := range thumbnails {
+		resized := fit(flat, thumbnail.MaxSide)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail %s: %w", thumbnail.Name, err)
+		}
+
+		result.Thumbnails[thumbnail.Name] = services.ImageVariant{
+			ContentType: "image/jpeg",
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+			Data:        buf.Bytes(),
+		}
+	}
+
+	return result, nil
+}
+
+// encodeOriginal перекодирует исходное изображение в его формате
+func encodeOriginal(img *image.RGBA, contentType string) (services.ImageVariant, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: originalJPEGQuality})
+	case "image/png":
+		err = png.Encode(&buf, img)
+	default:
+		err = errors.New("unsupported content type")
+	}
+	if err != nil {
+		return services.ImageVariant{}, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return services.ImageVariant{
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// toRGBA копирует изображение в RGBA с началом координат в (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// flatten накладывает изображение на белый фон
+func flatten(img *image.RGBA) *image.RGBA {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+	return flat
+}
@@ -0,0 +1,239 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Spoloborota/experiment/internal/config"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+// KeySet хранит ключ подписи и набор ключей проверки JWT
+type KeySet struct {
+	signing      services.SigningKey
+	verification map[string]services.VerificationKey
+	order        []string
+}
+
+// Load загружает ключи в соответствии с конфигурацией.
+// Для HS256 используется общий секрет, для RS256 и EdDSA — PEM файлы.
+func Load(cfg config.JWTConfig) (*KeySet, error) {
+	switch cfg.Algorithm {
+	case "HS256":
+		return newHMACKeySet(cfg)
+	case "RS256", "EdDSA":
+		return newAsymmetricKeySet(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// SigningKey возвращает текущий ключ подписи
+func (ks *KeySet) SigningKey() services.SigningKey {
+	return ks.signing
+}
+
+// VerificationKey возвращает ключ проверки по kid.
+// Токены без kid (выпущенные до появления ротации) проверяются ключом подписи.
+func (ks *KeySet) VerificationKey(kid string) (services.VerificationKey, bool) {
+	if kid == "" {
+		kid = ks.signing.ID
+	}
+	key, ok := ks.verification[kid]
+	return key, ok
+}
+
+// VerificationKeys возвращает все активные ключи проверки
+func (ks *KeySet) VerificationKeys() []services.VerificationKey {
+	keys := make([]services.VerificationKey, 0, len(ks.order))
+	for _, kid := range ks.order {
+		keys = append(keys, ks.verification[kid])
+	}
+	return keys
+}
+
+func (ks *KeySet) addVerificationKey(key services.VerificationKey) error {
+	if _, exists := ks.verification[key.ID]; exists {
+		return fmt.Errorf("duplicate JWT key id: %s", key.ID)
+	}
+	ks.verification[key.ID] = key
+	ks.order = append(ks.order, key.ID)
+	return nil
+}
+
+// newHMACKeySet создает набор из одного симметричного ключа
+func newHMACKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("JWT secret is required for HS256")
+	}
+
+	kid := cfg.SigningKeyID
+	if kid == "" {
+		kid = "hs256"
+	}
+
+	ks := &KeySet{
+		signing: services.SigningKey{
+			ID:     kid,
+			Method: jwt.SigningMethodHS256,
+			Key:    []byte(cfg.Secret),
+		},
+		verification: make(map[string]services.VerificationKey),
+	}
+
+	err := ks.addVerificationKey(services.VerificationKey{
+		ID:     kid,
+		Method: jwt.SigningMethodHS256,
+		Key:    []byte(cfg.Secret),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// newAsymmetricKeySet загружает приватный ключ подписи и дополнительные публичные ключи проверки
+func newAsymmetricKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	if cfg.SigningKeyFile == "" {
+		return nil, fmt.Errorf("JWT signing key file is required for %s", cfg.Algorithm)
+	}
+
+	data, err := os.ReadFile(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT signing key: %w", err)
+	}
+
+	var (
+		method     jwt.SigningMethod
+		privateKey crypto.Signer
+	)
+	switch cfg.Algorithm {
+	case "RS256":
+		method = jwt.SigningMethodRS256
+		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+	case "EdDSA":
+		method = jwt.SigningMethodEdDSA
+		privateKey, err = parseEdPrivateKey(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT signing key: %w", err)
+	}
+
+	kid := cfg.SigningKeyID
+	if kid == "" {
+		if kid, err = keyThumbprint(privateKey.Public()); err != nil {
+			return nil, err
+		}
+	}
+
+	ks := &KeySet{
+		signing: services.SigningKey{
+			ID:     kid,
+			Method: method,
+			Key:    privateKey,
+		},
+		verification: make(map[string]services.VerificationKey),
+	}
+
+	err = ks.addVerificationKey(services.VerificationKey{
+		ID:        kid,
+		Method:    method,
+		Key:       privateKey.Public(),
+		Published: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Дополнительные ключи проверки в формате kid=path или path
+	for _, entry := range cfg.VerificationKeyFiles {
+		key, err := loadVerificationKey(entry)
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.addVerificationKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// loadVerificationKey загружает публичный ключ из PEM файла
+func loadVerificationKey(entry string) (services.VerificationKey, error) {
+	kid, path := "", entry
+	if i := strings.Index(entry, "="); i >= 0 {
+		kid, path = entry[:i], entry[i+1:]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return services.VerificationKey{}, fmt.Errorf("failed to read JWT verification key %s: %w", path, err)
+	}
+
+	var (
+		method    jwt.SigningMethod
+		publicKey crypto.PublicKey
+	)
+	if rsaKey, rsaErr := jwt.ParseRSAPublicKeyFromPEM(data); rsaErr == nil {
+		method, publicKey = jwt.SigningMethodRS256, rsaKey
+	} else if edKey, edErr := jwt.ParseEdPublicKeyFromPEM(data); edErr == nil {
+		method, publicKey = jwt.SigningMethodEdDSA, edKey
+	} else {
+		return services.VerificationKey{}, fmt.Errorf("unsupported JWT verification key %s", path)
+	}
+
+	if kid == "" {
+		if kid, err = keyThumbprint(publicKey); err != nil {
+			return services.VerificationKey{}, err
+		}
+	}
+
+	return services.VerificationKey{
+		ID:        kid,
+		Method:    method,
+		Key:       publicKey,
+		Published: true,
+	}, nil
+}
+
+// parseEdPrivateKey разбирает приватный Ed25519 ключ и приводит его к crypto.Signer
+func parseEdPrivateKey(data []byte) (crypto.Signer, error) {
+	key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key is not an Ed25519 private key")
+	}
+	return signer, nil
+}
+
+// keyThumbprint вычисляет стабильный kid по публичному ключу
+func keyThumbprint(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12]), nil
+}
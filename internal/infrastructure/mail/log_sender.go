@@ -0,0 +1,30 @@
+package mail
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+type logSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender создает отправителя, который только пишет письма в лог.
+// Предназначен для локальной разработки.
+func NewLogSender(logger *zap.Logger) services.MailSender {
+	return &logSender{
+		logger: logger,
+	}
+}
+
+// Send записывает письмо в лог
+func (s *logSender) Send(ctx context.Context, msg services.MailMessage) error {
+	s.logger.Info("Mail message",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body))
+	return nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+type loginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]entities.LoginAttempts
+}
+
+// NewLoginAttemptStore создает хранилище счетчиков неудачных попыток входа в памяти процесса.
+// Счетчики не разделяются между экземплярами сервера и сбрасываются при перезапуске.
+func NewLoginAttemptStore() repositories.LoginAttemptStore {
+	return &loginAttemptStore{
+		attempts: make(map[string]entities.LoginAttempts),
+	}
+}
+
+// Get получает счетчик неудачных попыток по ключу
+func (s *loginAttemptStore) Get(ctx context.Context, key string) (*entities.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &attempts, nil
+}
+
+// CompareAndSwap заменяет счетчик, если он не изменился с момента чтения
+func (s *loginAttemptStore) CompareAndSwap(ctx context.Context, seen *entities.LoginAttempts, next entities.LoginAttempts) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.attempts[next.Key]
+	if ok != (seen != nil) {
+		return false, nil
+	}
+	if ok && (current.Failures != seen.Failures || !current.LastFailureAt.Equal(seen.LastFailureAt)) {
+		return false, nil
+	}
+
+	s.attempts[next.Key] = next
+	return true, nil
+}
+
+// Release уменьшает счетчик на одну попытку и восстанавливает время предыдущей неудачной попытки
+func (s *loginAttemptStore) Release(ctx context.Context, key string, reservedAt, previousFailureAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempts, ok := s.attempts[key]; ok && attempts.Failures > 0 {
+		attempts.Failures--
+		if attempts.LastFailureAt.Equal(reservedAt) {
+			attempts.LastFailureAt = previousFailureAt
+		}
+		s.attempts[key] = attempts
+	}
+	return nil
+}
+
+// Reset сбрасывает счетчик по ключу
+func (s *loginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// DeleteStale удаляет устаревшие счетчики
+func (s *loginAttemptStore) DeleteStale(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, attempts := range s.attempts {
+		if attempts.LastFailureAt.Before(before) {
+			delete(s.attempts, key)
+		}
+	}
+	return nil
+}
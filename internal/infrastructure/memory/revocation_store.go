@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+type revokedToken struct {
+	userID    int
+	expiresAt time.Time
+}
+
+type revocationStore struct {
+	mu            sync.RWMutex
+	tokens        map[string]revokedToken
+	revokedBefore map[int]time.Time
+}
+
+// NewRevocationStore создает хранилище отозванных токенов в памяти процесса.
+// Подходит для разработки и одного экземпляра сервера: данные теряются при перезапуске.
+func NewRevocationStore() repositories.RevocationStore {
+	return &revocationStore{
+		tokens:        make(map[string]revokedToken),
+		revokedBefore: make(map[int]time.Time),
+	}
+}
+
+// RevokeToken отзывает токен или сессию по идентификатору
+func (s *revocationStore) RevokeToken(ctx context.Context, tokenID string, userID int, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	s.tokens[tokenID] = revokedToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+// RevokeUserTokens отзывает все токены пользователя, выпущенные раньше issuedBefore
+func (s *revocationStore) RevokeUserTokens(ctx context.Context, userID int, issuedBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revokedBefore[userID] = issuedBefore
+	return nil
+}
+
+// IsRevoked проверяет, отозван ли токен
+func (s *revocationStore) IsRevoked(ctx context.Context, tokenIDs []string, userID int, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, id := range tokenIDs {
+		if token, ok := s.tokens[id]; ok && token.expiresAt.After(now) {
+			return true, nil
+		}
+	}
+
+	if before, ok := s.revokedBefore[userID]; ok && before.After(issuedAt) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// DeleteExpired удаляет записи, срок действия которых истек раньше before
+func (s *revocationStore) DeleteExpired(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, token := range s.tokens {
+		if token.expiresAt.Before(before) {
+			delete(s.tokens, id)
+		}
+	}
+	return nil
+}
+
+// removeExpired удаляет записи, срок действия которых уже истек
+func (s *revocationStore) removeExpired() {
+	now := time.Now()
+	for id, token := range s.tokens {
+		if !token.expiresAt.After(now) {
+			delete(s.tokens, id)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package passwordhash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Spoloborota/experiment/internal/config"
+)
+
+// algorithm описывает один алгоритм хеширования паролей
+type algorithm interface {
+	// matches проверяет, получен ли хеш этим алгоритмом
+	matches(encodedHash string) bool
+	hash(password string) (string, error)
+	verify(password, encodedHash string) bool
+	// outdated проверяет, отличаются ли параметры хеша от текущих
+	outdated(encodedHash string) bool
+}
+
+// Hasher хеширует пароли выбранным алгоритмом и проверяет хеши всех поддерживаемых алгоритмов,
+// чтобы пароли, сохраненные до смены алгоритма, продолжали работать
+type Hasher struct {
+	preferred  algorithm
+	algorithms []algorithm
+}
+
+// Load создает Hasher в соответствии с конфигурацией.
+// Новые хеши вычисляются алгоритмом cfg.Algorithm, проверяются argon2id и bcrypt.
+func Load(cfg config.PasswordHashConfig) (*Hasher, error) {
+	if cfg.Argon2MemoryKB <= 0 || cfg.Argon2Iterations <= 0 || cfg.Argon2Parallelism <= 0 || cfg.Argon2Parallelism > 255 {
+		return nil, fmt.Errorf("invalid argon2id parameters: memory, iterations and parallelism (1-255) must be positive")
+	}
+
+	argon, err := newArgon2id(Argon2idParams{
+		Memory:      uint32(cfg.Argon2MemoryKB),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+		SaltLength:  16,
+		KeyLength:   32,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bc, err := newBcrypt(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := &Hasher{algorithms: []algorithm{argon, bc}}
+	switch strings.ToLower(cfg.Algorithm) {
+	case "argon2id":
+		hasher.preferred = argon
+	case "bcrypt":
+		hasher.preferred = bc
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", cfg.Algorithm)
+	}
+
+	return hasher, nil
+}
+
+// Hash хеширует пароль текущим алгоритмом
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.hash(password)
+}
+
+// Verify проверяет пароль и сообщает, нужно ли пересчитать хеш текущим алгоритмом и параметрами
+func (h *Hasher) Verify(password, encodedHash string) (bool, bool) {
+	for _, alg := range h.algorithms {
+		if !alg.matches(encodedHash) {
+			continue
+		}
+		if !alg.verify(password, encodedHash) {
+			return false, false
+		}
+		return true, alg != h.preferred || alg.outdated(encodedHash)
+	}
+
+	return false, false
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type apiKeyRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewAPIKeyRepository создает новый экземпляр репозитория API ключей
+func NewAPIKeyRepository(db *sql.DB) repositories.APIKeyRepository {
+	return &apiKeyRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет новый API ключ
+func (r *apiKeyRepository) Create(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error) {
+	sqlcKey, err := r.queries.CreateAPIKey(ctx, sqlc.CreateAPIKeyParams{
+		UserID:  int32(key.UserID),
+		Name:    key.Name,
+		Prefix:  key.Prefix,
+		KeyHash: key.KeyHash,
+		Scopes:  key.Scopes,
+		ExpiresAt: sql.NullTime{
+			Time:  derefTime(key.ExpiresAt),
+			Valid: key.ExpiresAt != nil,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return r.convertToEntity(sqlcKey), nil
+}
+
+// GetByHash получает API ключ по хешу
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	sqlcKey, err := r.queries.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return r.convertToEntity(sqlcKey), nil
+}
+
+// ListByUser возвращает неотозванные ключи пользователя
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID int) ([]*entities.APIKey, error) {
+	sqlcKeys, err := r.queries.ListUserAPIKeys(ctx, int32(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys := make([]*entities.APIKey, len(sqlcKeys))
+	for i, sqlcKey := range sqlcKeys {
+		keys[i] = r.convertToEntity(sqlcKey)
+	}
+
+	return keys, nil
+}
+
+// Revoke отзывает ключ пользователя
+func (r *apiKeyRepository) Revoke(ctx context.Context, id, userID int) (bool, error) {
+	rows, err := r.queries.RevokeAPIKey(ctx, sqlc.RevokeAPIKeyParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// TouchLastUsed обновляет время последнего использования ключа
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int) error {
+	if err := r.queries.TouchAPIKeyLastUsed(ctx, int32(id)); err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *apiKeyRepository) convertToEntity(sqlcKey sqlc.ApiKey) *entities.APIKey {
+	var expiresAt, lastUsedAt, revokedAt *time.Time
+	if sqlcKey.ExpiresAt.Valid {
+		expiresAt = &sqlcKey.ExpiresAt.Time
+	}
+	if sqlcKey.LastUsedAt.Valid {
+		lastUsedAt = &sqlcKey.LastUsedAt.Time
+	}
+	if sqlcKey.RevokedAt.Valid {
+		revokedAt = &sqlcKey.RevokedAt.Time
+	}
+
+	scopes := []string(sqlcKey.Scopes)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &entities.APIKey{
+		ID:         int(sqlcKey.ID),
+		UserID:     int(sqlcKey.UserID),
+		Name:       sqlcKey.Name,
+		Prefix:     sqlcKey.Prefix,
+		KeyHash:    sqlcKey.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+		LastUsedAt: lastUsedAt,
+		RevokedAt:  revokedAt,
+		CreatedAt:  sqlcKey.CreatedAt,
+	}
+}
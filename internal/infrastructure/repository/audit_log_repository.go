@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type auditLogRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewAuditLogRepository создает новый экземпляр репозитория журнала аудита
+func NewAuditLogRepository(db *sql.DB) repositories.AuditLogRepository {
+	return &auditLogRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create добавляет запись в журнал
+func (r *auditLogRepository) Create(ctx context.Context, entry *entities.AuditLogEntry) error {
+	err := r.queries.CreateAuditLogEntry(ctx, sqlc.CreateAuditLogEntryParams{
+		ActorID:   int32(entry.ActorID),
+		UserID:    int32(entry.UserID),
+		Action:    entry.Action,
+		Method:    entry.Method,
+		Path:      entry.Path,
+		Status:    int32(entry.Status),
+		IP:        entry.IP,
+		RequestID: entry.RequestID,
+		Details:   entry.Details,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает записи журнала, новые первыми
+func (r *auditLogRepository) List(ctx context.Context, filter repositories.AuditLogFilter) ([]*entities.AuditLogEntry, error) {
+	sqlcEntries, err := r.queries.ListAuditLogEntries(ctx, sqlc.ListAuditLogEntriesParams{
+		ActorID:     int32(filter.ActorID),
+		UserID:      int32(filter.UserID),
+		LimitCount:  int32(filter.Limit),
+		OffsetCount: int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+
+	entries := make([]*entities.AuditLogEntry, len(sqlcEntries))
+	for i, sqlcEntry := range sqlcEntries {
+		entries[i] = r.convertToEntity(sqlcEntry)
+	}
+
+	return entries, nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *auditLogRepository) convertToEntity(sqlcEntry sqlc.AuditLog) *entities.AuditLogEntry {
+	return &entities.AuditLogEntry{
+		ID:        sqlcEntry.ID,
+		ActorID:   int(sqlcEntry.ActorID),
+		UserID:    int(sqlcEntry.UserID),
+		Action:    sqlcEntry.Action,
+		Method:    sqlcEntry.Method,
+		Path:      sqlcEntry.Path,
+		Status:    int(sqlcEntry.Status),
+		IP:        sqlcEntry.IP,
+		RequestID: sqlcEntry.RequestID,
+		Details:   sqlcEntry.Details,
+		CreatedAt: sqlcEntry.CreatedAt,
+	}
+}
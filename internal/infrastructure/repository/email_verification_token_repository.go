@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type emailVerificationTokenRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewEmailVerificationTokenRepository создает новый экземпляр репозитория токенов подтверждения email
+func NewEmailVerificationTokenRepository(db *sql.DB) repositories.EmailVerificationTokenRepository {
+	return &emailVerificationTokenRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет новый токен
+func (r *emailVerificationTokenRepository) Create(ctx context.Context, token *entities.EmailVerificationToken) (*entities.EmailVerificationToken, error) {
+	sqlcToken, err := r.queries.CreateEmailVerificationToken(ctx, sqlc.CreateEmailVerificationTokenParams{
+		UserID:    int32(token.UserID),
+		Email:     token.Email,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	return r.convertToEntity(sqlcToken), nil
+}
+
+// GetByHash получает токен по хешу
+func (r *emailVerificationTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.EmailVerificationToken, error) {
+	sqlcToken, err := r.queries.GetEmailVerificationTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("email verification token not found")
+		}
+		return nil, fmt.Errorf("failed to get email verification token: %w", err)
+	}
+
+	return r.convertToEntity(sqlcToken), nil
+}
+
+// MarkUsed помечает токен использованным
+func (r *emailVerificationTokenRepository) MarkUsed(ctx context.Context, id int) (bool, error) {
+	rows, err := r.queries.MarkEmailVerificationTokenUsed(ctx, int32(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to mark email verification token used: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// InvalidateForUser делает недействительными все неиспользованные токены пользователя
+func (r *emailVerificationTokenRepository) InvalidateForUser(ctx context.Context, userID int) error {
+	if err := r.queries.InvalidateUserEmailVerificationTokens(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to invalidate email verification tokens: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *emailVerificationTokenRepository) convertToEntity(sqlcToken sqlc.EmailVerificationToken) *entities.EmailVerificationToken {
+	var usedAt *time.Time
+	if sqlcToken.UsedAt.Valid {
+		usedAt = &sqlcToken.UsedAt.Time
+	}
+
+	return &entities.EmailVerificationToken{
+		ID:        int(sqlcToken.ID),
+		UserID:    int(sqlcToken.UserID),
+		Email:     sqlcToken.Email,
+		TokenHash: sqlcToken.TokenHash,
+		ExpiresAt: sqlcToken.ExpiresAt,
+		UsedAt:    usedAt,
+		CreatedAt: sqlcToken.CreatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type interestRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewInterestRepository создает новый экземпляр репозитория интересов
+func NewInterestRepository(db *sql.DB) repositories.InterestRepository {
+	return &interestRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// FindBySynonyms находит канонические интересы по нормализованным написаниям
+func (r *interestRepository) FindBySynonyms(ctx context.Context, synonyms []string) (map[string]*entities.Interest, error) {
+	rows, err := r.queries.FindInterestsBySynonyms(ctx, synonyms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interests: %w", err)
+	}
+
+	interests := make(map[string]*entities.Interest, len(rows))
+	for _, row := range rows {
+		interests[row.Synonym] = &entities.Interest{
+			ID:   int(row.ID),
+			Slug: row.Slug,
+		}
+	}
+
+	return interests, nil
+}
+
+// Create добавляет интерес вместе с названием и написаниями в одной транзакции
+func (r *interestRepository) Create(ctx context.Context, slug, locale, label string, synonyms []string) (*entities.Interest, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	sqlcInterest, err := qtx.CreateInterest(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create interest: %w", err)
+	}
+
+	err = qtx.AddInterestLabel(ctx, sqlc.AddInterestLabelParams{
+		InterestID: sqlcInterest.ID,
+		Locale:     locale,
+		Label:      label,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add interest label: %w", err)
+	}
+
+	for _, synonym := range append([]string{slug}, synonyms...) {
+		err := qtx.AddInterestSynonym(ctx, sqlc.AddInterestSynonymParams{
+			Synonym:    synonym,
+			InterestID: sqlcInterest.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add interest synonym: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &entities.Interest{
+		ID:    int(sqlcInterest.ID),
+		Slug:  sqlcInterest.Slug,
+		Label: label,
+	}, nil
+}
+
+// Suggest возвращает интересы по началу любого из написаний
+func (r *interestRepository) Suggest(ctx context.Context, prefix, locale string, limit int) ([]*entities.Interest, error) {
+	rows, err := r.queries.SuggestInterests(ctx, sqlc.SuggestInterestsParams{
+		Locale:     locale,
+		Prefix:     escapeLike(prefix),
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest interests: %w", err)
+	}
+
+	interests := make([]*entities.Interest, len(rows))
+	for i, row := range rows {
+		interests[i] = &entities.Interest{
+			ID:            int(row.ID),
+			Slug:          row.Slug,
+			Label:         row.Label,
+			ProfilesCount: int(row.ProfilesCount),
+		}
+	}
+
+	return interests, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE, чтобы строка сравнивалась буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type loginAttemptStore struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewLoginAttemptStore создает хранилище счетчиков неудачных попыток входа в PostgreSQL
+func NewLoginAttemptStore(db *sql.DB) repositories.LoginAttemptStore {
+	return &loginAttemptStore{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Get получает счетчик неудачных попыток по ключу
+func (s *loginAttemptStore) Get(ctx context.Context, key string) (*entities.LoginAttempts, error) {
+	sqlcAttempts, err := s.queries.GetLoginAttempts(ctx, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	return s.convertToEntity(sqlcAttempts), nil
+}
+
+// CompareAndSwap заменяет счетчик, если он не изменился с момента чтения
+func (s *loginAttemptStore) CompareAndSwap(ctx context.Context, seen *entities.LoginAttempts, next entities.LoginAttempts) (bool, error) {
+	var rows int64
+	var err error
+	if seen == nil {
+		rows, err = s.queries.InsertLoginAttempts(ctx, sqlc.InsertLoginAttemptsParams{
+			Key:           next.Key,
+			Failures:      int32(next.Failures),
+			LastFailureAt: next.LastFailureAt,
+		})
+	} else {
+		rows, err = s.queries.SwapLoginAttempts(ctx, sqlc.SwapLoginAttemptsParams{
+			Failures:          int32(next.Failures),
+			LastFailureAt:     next.LastFailureAt,
+			Key:               next.Key,
+			SeenFailures:      int32(seen.Failures),
+			SeenLastFailureAt: seen.LastFailureAt,
+		})
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update login attempts: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Release уменьшает счетчик на одну попытку и восстанавливает время предыдущей неудачной попытки
+func (s *loginAttemptStore) Release(ctx context.Context, key string, reservedAt, previousFailureAt time.Time) error {
+	err := s.queries.ReleaseLoginAttempt(ctx, sqlc.ReleaseLoginAttemptParams{
+		ReservedAt:        reservedAt,
+		PreviousFailureAt: previousFailureAt,
+		Key:               key,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release login attempt: %w", err)
+	}
+
+	return nil
+}
+
+// Reset сбрасывает счетчик по ключу
+func (s *loginAttemptStore) Reset(ctx context.Context, key string) error {
+	if err := s.queries.DeleteLoginAttempts(ctx, key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStale удаляет устаревшие счетчики
+func (s *loginAttemptStore) DeleteStale(ctx context.Context, before time.Time) error {
+	if err := s.queries.DeleteStaleLoginAttempts(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete stale login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (s *loginAttemptStore) convertToEntity(sqlcAttempts sqlc.LoginAttempt) *entities.LoginAttempts {
+	return &entities.LoginAttempts{
+		Key:           sqlcAttempts.Key,
+		Failures:      int(sqlcAttempts.Failures),
+		LastFailureAt: sqlcAttempts.LastFailureAt,
+	}
+}
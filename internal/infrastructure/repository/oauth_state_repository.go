@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type oauthStateRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewOAuthStateRepository создает новый экземпляр репозитория состояний входа через провайдера
+func NewOAuthStateRepository(db *sql.DB) repositories.OAuthStateRepository {
+	return &oauthStateRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет состояние входа
+func (r *oauthStateRepository) Create(ctx context.Context, state *entities.OAuthState) error {
+	err := r.queries.CreateOAuthState(ctx, sqlc.CreateOAuthStateParams{
+		StateHash:    state.StateHash,
+		Provider:     state.Provider,
+		CodeVerifier: state.CodeVerifier,
+		Nonce:        state.Nonce,
+		ExpiresAt:    state.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create oauth state: %w", err)
+	}
+
+	return nil
+}
+
+// Consume получает и удаляет состояние по хешу
+func (r *oauthStateRepository) Consume(ctx context.Context, stateHash string) (*entities.OAuthState, error) {
+	sqlcState, err := r.queries.ConsumeOAuthState(ctx, stateHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+
+	return &entities.OAuthState{
+		StateHash:    sqlcState.StateHash,
+		Provider:     sqlcState.Provider,
+		CodeVerifier: sqlcState.CodeVerifier,
+		Nonce:        sqlcState.Nonce,
+		ExpiresAt:    sqlcState.ExpiresAt,
+		CreatedAt:    sqlcState.CreatedAt,
+	}, nil
+}
+
+// DeleteExpired удаляет истекшие состояния
+func (r *oauthStateRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	if err := r.queries.DeleteExpiredOAuthStates(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete expired oauth states: %w", err)
+	}
+
+	return nil
+}
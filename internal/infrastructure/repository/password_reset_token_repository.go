@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type passwordResetTokenRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewPasswordResetTokenRepository создает новый экземпляр репозитория токенов сброса пароля
+func NewPasswordResetTokenRepository(db *sql.DB) repositories.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет новый токен
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *entities.PasswordResetToken) (*entities.PasswordResetToken, error) {
+	sqlcToken, err := r.queries.CreatePasswordResetToken(ctx, sqlc.CreatePasswordResetTokenParams{
+		UserID:    int32(token.UserID),
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return r.convertToEntity(sqlcToken), nil
+}
+
+// GetByHash получает токен по хешу
+func (r *passwordResetTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error) {
+	sqlcToken, err := r.queries.GetPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("password reset token not found")
+		}
+		return nil, fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	return r.convertToEntity(sqlcToken), nil
+}
+
+// MarkUsed помечает токен использованным
+func (r *passwordResetTokenRepository) MarkUsed(ctx context.Context, id int) (bool, error) {
+	rows, err := r.queries.MarkPasswordResetTokenUsed(ctx, int32(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to mark password reset token used: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// InvalidateForUser делает недействительными все неиспользованные токены пользователя
+func (r *passwordResetTokenRepository) InvalidateForUser(ctx context.Context, userID int) error {
+	if err := r.queries.InvalidateUserPasswordResetTokens(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *passwordResetTokenRepository) convertToEntity(sqlcToken sqlc.PasswordResetToken) *entities.PasswordResetToken {
+	var usedAt *time.Time
+	if sqlcToken.UsedAt.Valid {
+		usedAt = &sqlcToken.UsedAt.Time
+	}
+
+	return &entities.PasswordResetToken{
+		ID:        int(sqlcToken.ID),
+		UserID:    int(sqlcToken.UserID),
+		TokenHash: sqlcToken.TokenHash,
+		ExpiresAt: sqlcToken.ExpiresAt,
+		UsedAt:    usedAt,
+		CreatedAt: sqlcToken.CreatedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type profilePhotoRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewProfilePhotoRepository создает новый экземпляр репозитория фотографий анкет
+func NewProfilePhotoRepository(db *sql.DB) repositories.ProfilePhotoRepository {
+	return &profilePhotoRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет фотографию в конце списка
+func (r *profilePhotoRepository) Create(ctx context.Context, photo *entities.ProfilePhoto) (*entities.ProfilePhoto, error) {
+	sqlcPhoto, err := r.queries.CreateProfilePhoto(ctx, sqlc.CreateProfilePhotoParams{
+		ProfileID:   profileIDParam(photo.ProfileID),
+		StorageKey:  photo.StorageKey,
+		ContentType: photo.ContentType,
+		SizeBytes:   photo.SizeBytes,
+		Width:       int32(photo.Width),
+		Height:      int32(photo.Height),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create profile photo: %w", err)
+	}
+
+	return r.convertToEntity(sqlcPhoto), nil
+}
+
+// ListByProfile возвращает фотографии анкеты в порядке показа
+func (r *profilePhotoRepository) ListByProfile(ctx context.Context, profileID int) ([]*entities.ProfilePhoto, error) {
+	sqlcPhotos, err := r.queries.ListProfilePhotos(ctx, profileIDParam(profileID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profile photos: %w", err)
+	}
+
+	return r.convertAll(sqlcPhotos), nil
+}
+
+// ListByProfiles возвращает фотографии нескольких анкет, сгруппированные по ID анкеты
+func (r *profilePhotoRepository) ListByProfiles(ctx context.Context, profileIDs []int) (map[int][]*entities.ProfilePhoto, error) {
+	ids := make([]int32, len(profileIDs))
+	for i, id := range profileIDs {
+		ids[i] = int32(id)
+	}
+
+	sqlcPhotos, err := r.queries.ListPhotosByProfiles(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profile photos: %w", err)
+	}
+
+	photos := make(map[int][]*entities.ProfilePhoto, len(profileIDs))
+	for _, photo := range r.convertAll(sqlcPhotos) {
+		photos[photo.ProfileID] = append(photos[photo.ProfileID], photo)
+	}
+
+	return photos, nil
+}
+
+// Count возвращает количество фотографий анкеты
+func (r *profilePhotoRepository) Count(ctx context.Context, profileID int) (int, error) {
+	count, err := r.queries.CountProfilePhotos(ctx, profileIDParam(profileID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count profile photos: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// Reorder задает порядок фотографий в одной транзакции
+func (r *profilePhotoRepository) Reorder(ctx context.Context, profileID int, photoIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	for i, photoID := range photoIDs {
+		rows, err := qtx.SetProfilePhotoPosition(ctx, sqlc.SetProfilePhotoPositionParams{
+			ID:        int32(photoID),
+			ProfileID: profileIDParam(profileID),
+			Position:  int32(i + 1),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set photo position: %w", err)
+		}
+		if rows == 0 {
+			return repositories.ErrPhotoNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetPrimary делает фотографию основной, снимая отметку с прежней в той же транзакции
+func (r *profilePhotoRepository) SetPrimary(ctx context.Context, profileID, photoID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	if err := qtx.ClearPrimaryProfilePhoto(ctx, profileIDParam(profileID)); err != nil {
+		return fmt.Errorf("failed to clear primary photo: %w", err)
+	}
+
+	rows, err := qtx.SetPrimaryProfilePhoto(ctx, sqlc.SetPrimaryProfilePhotoParams{
+		ID:        int32(photoID),
+		ProfileID: profileIDParam(profileID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set primary photo: %w", err)
+	}
+	if rows == 0 {
+		return repositories.ErrPhotoNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Detach отвязывает фотографию от анкеты и при необходимости назначает новую основную
+func (r *profilePhotoRepository) Detach(ctx context.Context, profileID, photoID int) (*entities.ProfilePhoto, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	sqlcPhoto, err := qtx.DetachProfilePhoto(ctx, sqlc.DetachProfilePhotoParams{
+		ID:        int32(photoID),
+		ProfileID: profileIDParam(profileID),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("failed to detach profile photo: %w", err)
+	}
+
+	if err := qtx.PromoteFirstProfilePhoto(ctx, profileIDParam(profileID)); err != nil {
+		return nil, fmt.Errorf("failed to promote primary photo: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.convertToEntity(sqlcPhoto), nil
+}
+
+// ListDetached возвращает отвязанные фотографии
+func (r *profilePhotoRepository) ListDetached(ctx context.Context, limit int) ([]*entities.ProfilePhoto, error) {
+	sqlcPhotos, err := r.queries.ListOrphanedProfilePhotos(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list detached photos: %w", err)
+	}
+
+	return r.convertAll(sqlcPhotos), nil
+}
+
+// DeleteDetached удаляет запись об отвязанной фотографии
+func (r *profilePhotoRepository) DeleteDetached(ctx context.Context, photoID int) error {
+	if err := r.queries.DeleteProfilePhoto(ctx, int32(photoID)); err != nil {
+		return fmt.Errorf("failed to delete profile photo: %w", err)
+	}
+
+	return nil
+}
+
+// convertAll конвертирует список sqlc моделей в доменные сущности
+func (r *profilePhotoRepository) convertAll(sqlcPhotos []sqlc.ProfilePhoto) []*entities.ProfilePhoto {
+	photos := make([]*entities.ProfilePhoto, len(sqlcPhotos))
+	for i, sqlcPhoto := range sqlcPhotos {
+		photos[i] = r.convertToEntity(sqlcPhoto)
+	}
+	return photos
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *profilePhotoRepository) convertToEntity(sqlcPhoto sqlc.ProfilePhoto) *entities.ProfilePhoto {
+	return &entities.ProfilePhoto{
+		ID:          int(sqlcPhoto.ID),
+		ProfileID:   int(sqlcPhoto.ProfileID.Int32),
+		StorageKey:  sqlcPhoto.StorageKey,
+		ContentType: sqlcPhoto.ContentType,
+		SizeBytes:   sqlcPhoto.SizeBytes,
+		Width:       int(sqlcPhoto.Width),
+		Height:      int(sqlcPhoto.Height),
+		Position:    int(sqlcPhoto.Position),
+		IsPrimary:   sqlcPhoto.IsPrimary,
+		CreatedAt:   sqlcPhoto.CreatedAt,
+	}
+}
+
+// profileIDParam возвращает ID анкеты для nullable колонки profile_id
+func profileIDParam(profileID int) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(profileID), Valid: true}
+}
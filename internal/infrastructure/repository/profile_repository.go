@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/Spoloborota/experiment/internal/domain/entities"
 	"github.com/Spoloborota/experiment/internal/domain/repositories"
@@ -23,22 +26,29 @@ func NewProfileRepository(db *sql.DB) repositories.ProfileRepository {
 	}
 }
 
-// Create создает новый профиль
+// Create создает новый профиль вместе с интересами
 func (r *profileRepository) Create(ctx context.Context, profile *entities.Profile) (*entities.Profile, error) {
-	sqlcProfile, err := r.queries.CreateProfile(ctx, sqlc.CreateProfileParams{
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	sqlcProfile, err := qtx.CreateProfile(ctx, sqlc.CreateProfileParams{
 		UserID:    int32(profile.UserID),
 		FirstName: profile.FirstName,
 		LastName:  profile.LastName,
-		Age:       sql.NullInt32{Int32: int32(profile.Age), Valid: true},
+		BirthDate: birthDateParam(profile.BirthDate),
 		Gender:    sql.NullString{String: profile.Gender, Valid: profile.Gender != ""},
 		City:      sql.NullString{String: profile.City, Valid: profile.City != ""},
-		Interests: profile.Interests,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create profile: %w", err)
 	}
 
-	return r.convertToEntity(sqlcProfile), nil
+	return r.commitWithInterests(ctx, tx, qtx, sqlcProfile, profile.Interests)
 }
 
 // GetByID получает профиль по ID
@@ -46,12 +56,25 @@ func (r *profileRepository) GetByID(ctx context.Context, id int) (*entities.Prof
 	sqlcProfile, err := r.queries.GetProfileByID(ctx, int32(id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("profile not found")
+			return nil, repositories.ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to get profile by id: %w", err)
+	}
+
+	return r.toEntity(ctx, r.queries, sqlcProfile)
+}
+
+// GetByIDIncludingHidden получает профиль по ID, включая скрытые модератором
+func (r *profileRepository) GetByIDIncludingHidden(ctx context.Context, id int) (*entities.Profile, error) {
+	sqlcProfile, err := r.queries.GetProfileByIDIncludingHidden(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrProfileNotFound
 		}
 		return nil, fmt.Errorf("failed to get profile by id: %w", err)
 	}
 
-	return r.convertToEntity(sqlcProfile), nil
+	return r.toEntity(ctx, r.queries, sqlcProfile)
 }
 
 // GetByUserID получает профиль по ID пользователя
@@ -59,104 +82,367 @@ func (r *profileRepository) GetByUserID(ctx context.Context, userID int) (*entit
 	sqlcProfile, err := r.queries.GetProfileByUserID(ctx, int32(userID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("profile not found")
+			return nil, repositories.ErrProfileNotFound
 		}
 		return nil, fmt.Errorf("failed to get profile by user id: %w", err)
 	}
 
-	return r.convertToEntity(sqlcProfile), nil
+	return r.toEntity(ctx, r.queries, sqlcProfile)
 }
 
-// Update обновляет профиль
-func (r *profileRepository) Update(ctx context.Context, profile *entities.Profile) (*entities.Profile, error) {
-	sqlcProfile, err := r.queries.UpdateProfile(ctx, sqlc.UpdateProfileParams{
-		UserID:    int32(profile.UserID),
-		FirstName: profile.FirstName,
-		LastName:  profile.LastName,
-		Age:       sql.NullInt32{Int32: int32(profile.Age), Valid: true},
-		Gender:    sql.NullString{String: profile.Gender, Valid: profile.Gender != ""},
-		City:      sql.NullString{String: profile.City, Valid: profile.City != ""},
-		Interests: profile.Interests,
+// Update обновляет профиль вместе с интересами
+func (r *profileRepository) Update(ctx context.Context, profile *entities.Profile, expectedVersion int) (*entities.Profile, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	sqlcProfile, err := qtx.UpdateProfile(ctx, sqlc.UpdateProfileParams{
+		FirstName:       profile.FirstName,
+		LastName:        profile.LastName,
+		BirthDate:       birthDateParam(profile.BirthDate),
+		Gender:          sql.NullString{String: profile.Gender, Valid: profile.Gender != ""},
+		City:            sql.NullString{String: profile.City, Valid: profile.City != ""},
+		UserID:          int32(profile.UserID),
+		ExpectedVersion: int32(expectedVersion),
 	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.updateMissError(expectedVersion)
+		}
 		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
-	return r.convertToEntity(sqlcProfile), nil
+	return r.commitWithInterests(ctx, tx, qtx, sqlcProfile, profile.Interests)
 }
 
-// Search ищет профили по фильтрам
-func (r *profileRepository) Search(ctx context.Context, filters repositories.SearchFilters) ([]*entities.Profile, error) {
-	var gender string
-	if filters.Gender != nil {
-		gender = *filters.Gender
+// Patch сохраняет только поля анкеты, перечисленные в fields, не затрагивая остальные
+func (r *profileRepository) Patch(ctx context.Context, profile *entities.Profile, fields entities.ProfilePatch, expectedVersion int) (*entities.Profile, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	var city string
-	if filters.City != nil {
-		city = *filters.City
+	qtx := r.queries.WithTx(tx)
+
+	sqlcProfile, err := qtx.PatchProfile(ctx, sqlc.PatchProfileParams{
+		SetFirstName:    fields.FirstName != nil,
+		FirstName:       profile.FirstName,
+		SetLastName:     fields.LastName != nil,
+		LastName:        profile.LastName,
+		SetBirthDate:    fields.BirthDate != nil,
+		BirthDate:       birthDateParam(profile.BirthDate),
+		SetGender:       fields.Gender != nil,
+		Gender:          sql.NullString{String: profile.Gender, Valid: profile.Gender != ""},
+		SetCity:         fields.City != nil,
+		City:            sql.NullString{String: profile.City, Valid: profile.City != ""},
+		UserID:          int32(profile.UserID),
+		ExpectedVersion: int32(expectedVersion),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.updateMissError(expectedVersion)
+		}
+		return nil, fmt.Errorf("failed to patch profile: %w", err)
 	}
 
+	// Интересы заменяются, только если они есть в изменениях
 	var interests []string
-	if len(filters.Interests) > 0 {
-		interests = filters.Interests
+	if fields.Interests != nil {
+		interests = profile.Interests
+		if interests == nil {
+			interests = []string{}
+		}
 	}
 
-	sqlcProfiles, err := r.queries.SearchProfiles(ctx, sqlc.SearchProfilesParams{
-		Column1: gender,
-		Column2: city,
-		Column3: interests,
-		Limit:   int32(filters.Limit),
-		Offset:  int32(filters.Offset),
-	})
+	return r.commitWithInterests(ctx, tx, qtx, sqlcProfile, interests)
+}
+
+// updateMissError возвращает ошибку для обновления, не затронувшего ни одной строки.
+// Если ожидалась версия, анкета скорее всего изменена параллельным запросом
+func (r *profileRepository) updateMissError(expectedVersion int) error {
+	if expectedVersion != 0 {
+		return repositories.ErrProfileVersionMismatch
+	}
+	return repositories.ErrProfileNotFound
+}
+
+// Search ищет профили по фильтрам и возвращает курсор следующей страницы
+func (r *profileRepository) Search(ctx context.Context, filters repositories.SearchFilters) ([]*entities.Profile, *repositories.SearchCursor, error) {
+	search := newProfileSearch(filters, today())
+
+	// Лишняя анкета показывает, что следующая страница не пуста
+	query, args, err := search.selectPage(filters.Limit+1, filters.Offset, filters.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search profiles: %w", err)
+		return nil, nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int32
+	var lastKeys []interface{}
+	more := false
+	for rows.Next() {
+		if len(ids) == filters.Limit {
+			more = true
+			break
+		}
+
+		var id int32
+		keys := search.keyDests()
+		if err := rows.Scan(append([]interface{}{&id}, keys...)...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan profile id: %w", err)
+		}
+		ids = append(ids, id)
+		lastKeys = keys
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to search profiles: %w", err)
 	}
 
-	profiles := make([]*entities.Profile, len(sqlcProfiles))
-	for i, sqlcProfile := range sqlcProfiles {
-		profiles[i] = r.convertToEntity(sqlcProfile)
+	var next *repositories.SearchCursor
+	if more {
+		next = search.cursor(lastKeys)
+	}
+
+	if len(ids) == 0 {
+		return []*entities.Profile{}, nil, nil
+	}
+
+	sqlcProfiles, err := r.queries.ListProfilesByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list profiles: %w", err)
 	}
 
-	return profiles, nil
+	// Возвращаем анкеты в порядке, найденном поиском
+	byID := make(map[int32]sqlc.Profile, len(sqlcProfiles))
+	for _, sqlcProfile := range sqlcProfiles {
+		byID[sqlcProfile.ID] = sqlcProfile
+	}
+
+	profiles := make([]*entities.Profile, 0, len(ids))
+	for _, id := range ids {
+		if sqlcProfile, ok := byID[id]; ok {
+			profiles = append(profiles, r.convertToEntity(sqlcProfile))
+		}
+	}
+
+	if err := r.loadInterests(ctx, r.queries, profiles...); err != nil {
+		return nil, nil, err
+	}
+
+	if filters.Query != nil {
+		if err := r.loadSearchMatches(ctx, filters, profiles); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return profiles, next, nil
 }
 
 // Count возвращает количество профилей по фильтрам
 func (r *profileRepository) Count(ctx context.Context, filters repositories.SearchFilters) (int, error) {
-	var gender string
-	if filters.Gender != nil {
-		gender = *filters.Gender
+	query, args := newProfileSearch(filters, today()).count()
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count profiles: %w", err)
 	}
 
-	var city string
-	if filters.City != nil {
-		city = *filters.City
+	return count, nil
+}
+
+// birthDateParam переводит дату рождения в параметр запроса; нулевая дата сохраняется как NULL
+func birthDateParam(date entities.Date) sql.NullTime {
+	return sql.NullTime{Time: date.Time, Valid: !date.IsZero()}
+}
+
+// UpdatePrivacy сохраняет настройки видимости анкеты
+func (r *profileRepository) UpdatePrivacy(ctx context.Context, userID int, privacy entities.ProfilePrivacy) (*entities.Profile, error) {
+	sqlcProfile, err := r.queries.UpdateProfilePrivacy(ctx, sqlc.UpdateProfilePrivacyParams{
+		ProfileVisibility:   string(privacy.Profile),
+		AgeVisibility:       string(privacy.Age),
+		BirthdayVisibility:  string(privacy.Birthday),
+		ZodiacVisibility:    string(privacy.Zodiac),
+		GenderVisibility:    string(privacy.Gender),
+		CityVisibility:      string(privacy.City),
+		InterestsVisibility: string(privacy.Interests),
+		PhotosVisibility:    string(privacy.Photos),
+		UserID:              int32(userID),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to update profile privacy: %w", err)
 	}
 
-	var interests []string
-	if len(filters.Interests) > 0 {
-		interests = filters.Interests
+	return r.toEntity(ctx, r.queries, sqlcProfile)
+}
+
+// BumpVersion увеличивает версию анкеты
+func (r *profileRepository) BumpVersion(ctx context.Context, id int) error {
+	if err := r.queries.BumpProfileVersion(ctx, int32(id)); err != nil {
+		return fmt.Errorf("failed to bump profile version: %w", err)
 	}
 
-	count, err := r.queries.GetProfilesCount(ctx, sqlc.GetProfilesCountParams{
-		Column1: gender,
-		Column2: city,
-		Column3: interests,
+	return nil
+}
+
+// SetHidden скрывает анкету или снова делает ее видимой
+func (r *profileRepository) SetHidden(ctx context.Context, id int, hidden bool) (*entities.Profile, error) {
+	sqlcProfile, err := r.queries.SetProfileHidden(ctx, sqlc.SetProfileHiddenParams{
+		Hidden: hidden,
+		ID:     int32(id),
 	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to count profiles: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to set profile visibility: %w", err)
 	}
 
-	return int(count), nil
+	return r.toEntity(ctx, r.queries, sqlcProfile)
 }
 
-// convertToEntity конвертирует sqlc модель в доменную сущность
-func (r *profileRepository) convertToEntity(sqlcProfile sqlc.Profile) *entities.Profile {
-	var age int
-	if sqlcProfile.Age.Valid {
-		age = int(sqlcProfile.Age.Int32)
+// commitWithInterests заменяет интересы сохраненной анкеты, если interests не nil,
+// фиксирует транзакцию и возвращает анкету с интересами
+func (r *profileRepository) commitWithInterests(ctx context.Context, tx *sql.Tx, qtx *sqlc.Queries, sqlcProfile sqlc.Profile, interests []string) (*entities.Profile, error) {
+	if interests != nil {
+		if err := qtx.DeleteProfileInterests(ctx, sqlcProfile.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete profile interests: %w", err)
+		}
+
+		err := qtx.AddProfileInterests(ctx, sqlc.AddProfileInterestsParams{
+			ProfileID: sqlcProfile.ID,
+			Slugs:     interests,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add profile interests: %w", err)
+		}
 	}
 
+	profile, err := r.toEntity(ctx, qtx, sqlcProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return profile, nil
+}
+
+// toEntity конвертирует sqlc модель в доменную сущность и загружает интересы анкеты
+func (r *profileRepository) toEntity(ctx context.Context, q *sqlc.Queries, sqlcProfile sqlc.Profile) (*entities.Profile, error) {
+	profile := r.convertToEntity(sqlcProfile)
+	if err := r.loadInterests(ctx, q, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// loadInterests загружает slug интересов нескольких анкет одним запросом
+func (r *profileRepository) loadInterests(ctx context.Context, q *sqlc.Queries, profiles ...*entities.Profile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	ids := make([]int32, len(profiles))
+	for i, profile := range profiles {
+		ids[i] = int32(profile.ID)
+	}
+
+	rows, err := q.ListProfileInterests(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list profile interests: %w", err)
+	}
+
+	interests := make(map[int][]string, len(profiles))
+	for _, row := range rows {
+		interests[int(row.ProfileID)] = append(interests[int(row.ProfileID)], row.Slug)
+	}
+
+	for _, profile := range profiles {
+		profile.Interests = interests[profile.ID]
+		if profile.Interests == nil {
+			profile.Interests = []string{}
+		}
+	}
+
+	return nil
+}
+
+// loadSearchMatches дополняет найденные по текстовому запросу анкеты релевантностью и выделенными совпадениями
+func (r *profileRepository) loadSearchMatches(ctx context.Context, filters repositories.SearchFilters, profiles []*entities.Profile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	ids := make([]int32, len(profiles))
+	for i, profile := range profiles {
+		ids[i] = int32(profile.ID)
+	}
+
+	rows, err := r.queries.ListProfileSearchMatches(ctx, sqlc.ListProfileSearchMatchesParams{
+		ViewerID:   int32(filters.ViewerID),
+		FriendIds:  int32IDs(filters.ViewerFriendIDs),
+		Q:          *filters.Query,
+		ProfileIds: ids,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list profile search matches: %w", err)
+	}
+
+	matches := make(map[int]*entities.SearchMatch, len(rows))
+	for _, row := range rows {
+		match := &entities.SearchMatch{
+			Score:      row.Score,
+			Highlights: make(map[string]string),
+		}
+
+		fields := []struct {
+			name  string
+			value sql.NullString
+		}{
+			{entities.SearchFieldName, row.Names},
+			{entities.SearchFieldCity, row.City},
+			{entities.SearchFieldInterests, row.Interests},
+		}
+		for _, field := range fields {
+			if field.value.Valid {
+				match.Highlights[field.name] = highlightHTML(field.value.String)
+			}
+		}
+
+		matches[int(row.ProfileID)] = match
+	}
+
+	for _, profile := range profiles {
+		profile.Match = matches[profile.ID]
+	}
+
+	return nil
+}
+
+// highlightHTML экранирует текст для HTML и заменяет границы совпадений U+0001 и U+0002,
+// которые расставляет база данных, тегом <mark>
+func highlightHTML(value string) string {
+	return strings.NewReplacer("\x01", "<mark>", "\x02", "</mark>").Replace(html.EscapeString(value))
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *profileRepository) convertToEntity(sqlcProfile sqlc.Profile) *entities.Profile {
 	var gender string
 	if sqlcProfile.Gender.Valid {
 		gender = sqlcProfile.Gender.String
@@ -167,21 +453,37 @@ func (r *profileRepository) convertToEntity(sqlcProfile sqlc.Profile) *entities.
 		city = sqlcProfile.City.String
 	}
 
-	interests := []string(sqlcProfile.Interests)
-	if interests == nil {
-		interests = []string{}
+	var hiddenAt *time.Time
+	if sqlcProfile.HiddenAt.Valid {
+		hiddenAt = &sqlcProfile.HiddenAt.Time
 	}
 
-	return &entities.Profile{
+	profile := &entities.Profile{
 		ID:        int(sqlcProfile.ID),
 		UserID:    int(sqlcProfile.UserID),
 		FirstName: sqlcProfile.FirstName,
 		LastName:  sqlcProfile.LastName,
-		Age:       age,
 		Gender:    gender,
 		City:      city,
-		Interests: interests,
+		HiddenAt:  hiddenAt,
+		Version:   int(sqlcProfile.Version),
+		Privacy: &entities.ProfilePrivacy{
+			Profile:   entities.Visibility(sqlcProfile.ProfileVisibility),
+			Age:       entities.Visibility(sqlcProfile.AgeVisibility),
+			Birthday:  entities.Visibility(sqlcProfile.BirthdayVisibility),
+			Zodiac:    entities.Visibility(sqlcProfile.ZodiacVisibility),
+			Gender:    entities.Visibility(sqlcProfile.GenderVisibility),
+			City:      entities.Visibility(sqlcProfile.CityVisibility),
+			Interests: entities.Visibility(sqlcProfile.InterestsVisibility),
+			Photos:    entities.Visibility(sqlcProfile.PhotosVisibility),
+		},
 		CreatedAt: sqlcProfile.CreatedAt,
 		UpdatedAt: sqlcProfile.UpdatedAt,
 	}
+
+	if sqlcProfile.BirthDate.Valid {
+		profile.SetBirthDate(entities.DateOf(sqlcProfile.BirthDate.Time))
+	}
+
+	return profile
 }
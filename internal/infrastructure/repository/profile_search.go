@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+// profileSearch собирает запрос поиска анкет по фильтрам. В запрос попадают только условия
+// заданных фильтров, каждое из которых сравнивает столбец с параметром, поэтому планировщик
+// видит простые предикаты и использует индексы по полу, городу, дате рождения, имени и тексту.
+// Фильтр по полю, скрытому от зрителя, не совпадает, а исключение по нему анкету не исключает
+type profileSearch struct {
+	conditions []string
+	args       []interface{}
+	viewer     string // Параметры зрителя и его друзей для profile_field_visible
+	query      string // Параметр текстового запроса, пустой без него
+	sort       repositories.SearchSort
+	keys       []searchKey // Ключи сортировки; последний - ID, поэтому порядок полный
+	descending bool
+}
+
+// searchKeyKind - тип значения ключа сортировки, определяет его запись в курсоре
+type searchKeyKind int
+
+const (
+	searchKeyInt searchKeyKind = iota
+	searchKeyReal
+	searchKeyTime
+	searchKeyText
+)
+
+// searchKey - выражение, по которому упорядочиваются результаты поиска
+type searchKey struct {
+	expr string
+	kind searchKeyKind
+}
+
+// newProfileSearch строит условия поиска по фильтрам на дату today
+func newProfileSearch(filters repositories.SearchFilters, today entities.Date) *profileSearch {
+	s := &profileSearch{}
+
+	s.viewer = s.arg(int32(filters.ViewerID)) + "::int, " + s.arg(pq.Array(int32IDs(filters.ViewerFriendIDs))) + "::int[]"
+
+	s.where("profile_field_visible(profile_visibility, user_id, " + s.viewer + ")")
+	s.where("hidden_at IS NULL")
+	s.where(`NOT EXISTS (
+		SELECT 1 FROM users
+		WHERE users.id = profiles.user_id AND users.deleted_at IS NOT NULL
+	)`)
+
+	if len(filters.Genders) > 0 {
+		s.whereVisible("gender_visibility", "gender = ANY("+s.arg(pq.Array(filters.Genders))+"::text[])")
+	}
+	if len(filters.ExcludeGenders) > 0 {
+		s.exclude("gender_visibility", "gender = ANY("+s.arg(pq.Array(filters.ExcludeGenders))+"::text[])")
+	}
+
+	if len(filters.Cities) > 0 {
+		s.whereVisible("city_visibility", "city = ANY("+s.arg(pq.Array(filters.Cities))+"::text[])")
+	}
+	if len(filters.ExcludeCities) > 0 {
+		s.exclude("city_visibility", "city = ANY("+s.arg(pq.Array(filters.ExcludeCities))+"::text[])")
+	}
+
+	if len(filters.Interests) > 0 {
+		slugs := uniqueStrings(filters.Interests)
+		if filters.InterestsMatch == repositories.InterestsMatchAll {
+			s.whereVisible("interests_visibility", fmt.Sprintf(`(
+				SELECT COUNT(*) FROM profile_interests
+				JOIN interests ON interests.id = profile_interests.interest_id
+				WHERE profile_interests.profile_id = profiles.id AND interests.slug = ANY(%s::text[])
+			) = %s`, s.arg(pq.Array(slugs)), s.arg(len(slugs))))
+		} else {
+			s.whereVisible("interests_visibility", s.hasInterest(slugs))
+		}
+	}
+	if len(filters.ExcludeInterests) > 0 {
+		s.exclude("interests_visibility", s.hasInterest(filters.ExcludeInterests))
+	}
+
+	// Возраст переводится в диапазон дат рождения, чтобы использовать индекс по дате рождения
+	if filters.BirthDateFrom != nil {
+		s.whereVisible("age_visibility", "birth_date >= "+s.arg(birthDateParam(*filters.BirthDateFrom)))
+	}
+	if filters.BirthDateTo != nil {
+		s.whereVisible("age_visibility", "birth_date <= "+s.arg(birthDateParam(*filters.BirthDateTo)))
+	}
+	if filters.AgeMin != nil {
+		bornBy := entities.LatestBirthDate(*filters.AgeMin, today)
+		s.whereVisible("age_visibility", "birth_date <= "+s.arg(birthDateParam(bornBy)))
+	}
+	if filters.AgeMax != nil {
+		bornAfter := entities.LatestBirthDate(*filters.AgeMax+1, today)
+		s.whereVisible("age_visibility", "birth_date > "+s.arg(birthDateParam(bornAfter)))
+	}
+
+	// Имя и фамилия видны всем, кому видна анкета
+	if filters.FirstName != nil {
+		s.where("lower(first_name) LIKE lower(" + s.arg(escapeLike(*filters.FirstName)+"%") + ")")
+	}
+	if filters.LastName != nil {
+		s.where("lower(last_name) LIKE lower(" + s.arg(escapeLike(*filters.LastName)+"%") + ")")
+	}
+
+	// Кандидаты отбираются по индексам документов, а оценка отсеивает совпадения в скрытых полях
+	if filters.Query != nil {
+		s.query = s.arg(*filters.Query) + "::text"
+		s.where(fmt.Sprintf(`id IN (
+			SELECT profile_id FROM profile_search_documents
+			WHERE document @@ profile_search_query(%[1]s) OR
+				names %% lower(%[1]s) OR
+				city %% lower(%[1]s)
+		)`, s.query))
+		s.where(s.score() + " > 0")
+	}
+
+	s.orderBy(filters.Sort, today)
+
+	return s
+}
+
+// orderBy задает ключи сортировки. Все ключи упорядочиваются в одном направлении, поэтому
+// позиция курсора сравнивается с ними одним сравнением строк, а не цепочкой условий
+func (s *profileSearch) orderBy(sort repositories.SearchSort, today entities.Date) {
+	id := searchKey{expr: "id", kind: searchKeyInt}
+	createdAt := searchKey{expr: "created_at", kind: searchKeyTime}
+
+	switch {
+	case sort == repositories.SearchSortName:
+		s.keys = []searchKey{
+			{expr: "lower(first_name)", kind: searchKeyText},
+			{expr: "lower(last_name)", kind: searchKeyText},
+			id,
+		}
+	case sort == repositories.SearchSortAge:
+		// Сортировка по скрытому возрасту раскрыла бы его, поэтому такие анкеты идут в конце.
+		// Ключ - полные годы, а не дата рождения: она видна только владельцу, а курсор - клиенту
+		age := fmt.Sprintf("CASE WHEN birth_date IS NOT NULL AND %s THEN date_part('year', age(%s::date, birth_date))::int ELSE %s::int END",
+			s.visible("age_visibility"), s.arg(birthDateParam(today)), s.arg(math.MaxInt32))
+		s.keys = []searchKey{{expr: age, kind: searchKeyInt}, id}
+	case sort == repositories.SearchSortRelevance && s.query != "":
+		s.keys = []searchKey{{expr: s.score(), kind: searchKeyReal}, createdAt, id}
+		s.descending = true
+	default:
+		sort = repositories.SearchSortNewest
+		s.keys = []searchKey{createdAt, id}
+		s.descending = true
+	}
+
+	s.sort = sort
+}
+
+// selectPage возвращает запрос страницы найденных анкет: ID и значения ключей сортировки.
+// Страница начинается после позиции after, если она задана, иначе пропускает offset анкет
+func (s *profileSearch) selectPage(limit, offset int, after *repositories.SearchCursor) (string, []interface{}, error) {
+	direction, compare := "ASC", ">"
+	if s.descending {
+		direction, compare = "DESC", "<"
+	}
+
+	columns := make([]string, len(s.keys))
+	exprs := make([]string, len(s.keys))
+	order := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		columns[i] = fmt.Sprintf("%s AS key%d", key.expr, i)
+		exprs[i] = key.expr
+		order[i] = fmt.Sprintf("key%d %s", i, direction)
+	}
+
+	if after != nil {
+		values, err := s.cursorValues(after)
+		if err != nil {
+			return "", nil, err
+		}
+		s.where(fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), compare, strings.Join(values, ", ")))
+		offset = 0
+	}
+
+	query := fmt.Sprintf("SELECT id, %s FROM profiles\nWHERE %s\nORDER BY %s\nLIMIT %s OFFSET %s",
+		strings.Join(columns, ", "), strings.Join(s.conditions, "\n    AND "), strings.Join(order, ", "), s.arg(limit), s.arg(offset))
+	return query, s.args, nil
+}
+
+// cursorValues добавляет значения ключей из курсора параметрами запроса
+func (s *profileSearch) cursorValues(cursor *repositories.SearchCursor) ([]string, error) {
+	if cursor.Sort != s.sort || len(cursor.Keys) != len(s.keys) {
+		return nil, repositories.ErrInvalidSearchCursor
+	}
+
+	values := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		value := cursor.Keys[i]
+		var err error
+		switch key.kind {
+		case searchKeyInt:
+			var parsed int64
+			parsed, err = strconv.ParseInt(value, 10, 32)
+			values[i] = s.arg(parsed) + "::int"
+		case searchKeyReal:
+			var parsed float64
+			parsed, err = strconv.ParseFloat(value, 32)
+			values[i] = s.arg(parsed) + "::real"
+		case searchKeyTime:
+			var parsed time.Time
+			parsed, err = time.Parse(time.RFC3339Nano, value)
+			values[i] = s.arg(parsed) + "::timestamptz"
+		case searchKeyText:
+			values[i] = s.arg(value) + "::text"
+		}
+		if err != nil {
+			return nil, repositories.ErrInvalidSearchCursor
+		}
+	}
+
+	return values, nil
+}
+
+// keyDests возвращает приемники для сканирования значений ключей сортировки
+func (s *profileSearch) keyDests() []interface{} {
+	dests := make([]interface{}, len(s.keys))
+	for i, key := range s.keys {
+		switch key.kind {
+		case searchKeyInt:
+			dests[i] = new(int64)
+		case searchKeyReal:
+			dests[i] = new(float32)
+		case searchKeyTime:
+			dests[i] = new(time.Time)
+		case searchKeyText:
+			dests[i] = new(string)
+		}
+	}
+	return dests
+}
+
+// cursor возвращает курсор позиции анкеты со значениями ключей из keyDests. Значения записываются
+// без потери точности, чтобы сравнение в следующем запросе начиналось ровно после этой анкеты
+func (s *profileSearch) cursor(dests []interface{}) *repositories.SearchCursor {
+	keys := make([]string, len(dests))
+	for i, dest := range dests {
+		switch value := dest.(type) {
+		case *int64:
+			keys[i] = strconv.FormatInt(*value, 10)
+		case *float32:
+			keys[i] = strconv.FormatFloat(float64(*value), 'g', -1, 32)
+		case *time.Time:
+			keys[i] = value.Format(time.RFC3339Nano)
+		case *string:
+			keys[i] = *value
+		}
+	}
+	return &repositories.SearchCursor{Sort: s.sort, Keys: keys}
+}
+
+// count возвращает запрос количества найденных анкет
+func (s *profileSearch) count() (string, []interface{}) {
+	return "SELECT COUNT(*) FROM profiles\nWHERE " + strings.Join(s.conditions, "\n    AND "), s.args
+}
+
+// arg добавляет параметр запроса и возвращает ссылку на него
+func (s *profileSearch) arg(value interface{}) string {
+	s.args = append(s.args, value)
+	return fmt.Sprintf("$%d", len(s.args))
+}
+
+// where добавляет условие, которое должно выполняться
+func (s *profileSearch) where(condition string) {
+	s.conditions = append(s.conditions, condition)
+}
+
+// whereVisible добавляет условие на поле, которое совпадает, только если поле видно зрителю
+func (s *profileSearch) whereVisible(visibility, condition string) {
+	s.where(condition + " AND " + s.visible(visibility))
+}
+
+// exclude исключает анкеты, для которых выполняется condition. Если поле скрыто от зрителя
+// или не заполнено, анкета не исключается
+func (s *profileSearch) exclude(visibility, condition string) {
+	s.where("NOT coalesce(" + condition + " AND " + s.visible(visibility) + ", false)")
+}
+
+// visible возвращает условие видимости поля зрителю
+func (s *profileSearch) visible(visibility string) string {
+	return "profile_field_visible(" + visibility + ", user_id, " + s.viewer + ")"
+}
+
+// hasInterest возвращает условие наличия у анкеты хотя бы одного из интересов
+func (s *profileSearch) hasInterest(slugs []string) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM profile_interests
+		JOIN interests ON interests.id = profile_interests.interest_id
+		WHERE profile_interests.profile_id = profiles.id AND interests.slug = ANY(%s::text[])
+	)`, s.arg(pq.Array(slugs)))
+}
+
+// score возвращает выражение релевантности анкеты текстовому запросу
+func (s *profileSearch) score() string {
+	return "profile_search_score(id, user_id, city_visibility, interests_visibility, " + s.viewer + ", " + s.query + ")"
+}
+
+// uniqueStrings возвращает значения без повторов в исходном порядке
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			unique = append(unique, value)
+			seen[value] = true
+		}
+	}
+	return unique
+}
+
+// int32IDs переводит идентификаторы в тип параметров запросов
+func int32IDs(ids []int) []int32 {
+	converted := make([]int32, len(ids))
+	for i, id := range ids {
+		converted[i] = int32(id)
+	}
+	return converted
+}
+
+// today возвращает текущую дату, на которую вычисляется возраст в фильтрах
+func today() entities.Date {
+	return entities.DateOf(time.Now())
+}
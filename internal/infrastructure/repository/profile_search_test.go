@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+)
+
+var searchTestToday = entities.NewDate(2026, time.October, 17)
+
+func TestProfileSearchOrder(t *testing.T) {
+	query := "анна"
+	ageKey := "CASE WHEN birth_date IS NOT NULL AND profile_field_visible(age_visibility, user_id, $1::int, $2::int[]) " +
+		"THEN date_part('year', age($3::date, birth_date))::int ELSE $4::int END"
+	scoreKey := "profile_search_score(id, user_id, city_visibility, interests_visibility, $1::int, $2::int[], $3::text)"
+
+	tests := []struct {
+		name      string
+		filters   repositories.SearchFilters
+		wantSort  repositories.SearchSort
+		wantKeys  []string
+		wantOrder string
+		wantAfter string // Условие позиции курсора без номеров параметров
+	}{
+		{
+			name:      "default is newest",
+			wantSort:  repositories.SearchSortNewest,
+			wantKeys:  []string{"created_at", "id"},
+			wantOrder: "ORDER BY key0 DESC, key1 DESC",
+			wantAfter: "(created_at, id) < ($::timestamptz, $::int)",
+		},
+		{
+			name:      "newest",
+			filters:   repositories.SearchFilters{Sort: repositories.SearchSortNewest},
+			wantSort:  repositories.SearchSortNewest,
+			wantKeys:  []string{"created_at", "id"},
+			wantOrder: "ORDER BY key0 DESC, key1 DESC",
+			wantAfter: "(created_at, id) < ($::timestamptz, $::int)",
+		},
+		{
+			name:      "name",
+			filters:   repositories.SearchFilters{Sort: repositories.SearchSortName},
+			wantSort:  repositories.SearchSortName,
+			wantKeys:  []string{"lower(first_name)", "lower(last_name)", "id"},
+			wantOrder: "ORDER BY key0 ASC, key1 ASC, key2 ASC",
+			wantAfter: "(lower(first_name), lower(last_name), id) > ($::text, $::text, $::int)",
+		},
+		{
+			name:      "age",
+			filters:   repositories.SearchFilters{Sort: repositories.SearchSortAge},
+			wantSort:  repositories.SearchSortAge,
+			wantKeys:  []string{ageKey, "id"},
+			wantOrder: "ORDER BY key0 ASC, key1 ASC",
+			wantAfter: ") > ($::int, $::int)",
+		},
+		{
+			name:      "relevance",
+			filters:   repositories.SearchFilters{Sort: repositories.SearchSortRelevance, Query: &query},
+			wantSort:  repositories.SearchSortRelevance,
+			wantKeys:  []string{scoreKey, "created_at", "id"},
+			wantOrder: "ORDER BY key0 DESC, key1 DESC, key2 DESC",
+			wantAfter: ", created_at, id) < ($::real, $::timestamptz, $::int)",
+		},
+		{
+			name:      "relevance without query is newest",
+			filters:   repositories.SearchFilters{Sort: repositories.SearchSortRelevance},
+			wantSort:  repositories.SearchSortNewest,
+			wantKeys:  []string{"created_at", "id"},
+			wantOrder: "ORDER BY key0 DESC, key1 DESC",
+			wantAfter: "(created_at, id) < ($::timestamptz, $::int)",
+		},
+	}
+
+	paramNumbers := regexp.MustCompile(`\$\d+`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := newProfileSearch(tt.filters, searchTestToday)
+			if search.sort != tt.wantSort {
+				t.Errorf("sort = %q, want %q", search.sort, tt.wantSort)
+			}
+
+			keys := make([]string, len(search.keys))
+			for i, key := range search.keys {
+				keys[i] = key.expr
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("keys = %q, want %q", keys, tt.wantKeys)
+			}
+
+			query, args, err := search.selectPage(10, 20, nil)
+			if err != nil {
+				t.Fatalf("selectPage() error = %v", err)
+			}
+			if !strings.Contains(query, tt.wantOrder) {
+				t.Errorf("query has no %q:\n%s", tt.wantOrder, query)
+			}
+			if got := args[len(args)-2:]; !reflect.DeepEqual(got, []interface{}{10, 20}) {
+				t.Errorf("limit and offset = %v, want [10 20]", got)
+			}
+
+			// Ключи страницы с курсором сравниваются с ним одним сравнением строк
+			search = newProfileSearch(tt.filters, searchTestToday)
+			cursor := search.cursor(searchTestKeys(search))
+			query, args, err = search.selectPage(10, 20, cursor)
+			if err != nil {
+				t.Fatalf("selectPage() with cursor error = %v", err)
+			}
+			if after := paramNumbers.ReplaceAllString(query, "$$"); !strings.Contains(after, tt.wantAfter) {
+				t.Errorf("query has no %q:\n%s", tt.wantAfter, query)
+			}
+			if !strings.Contains(query, tt.wantOrder) {
+				t.Errorf("query with cursor has no %q:\n%s", tt.wantOrder, query)
+			}
+			if offset := args[len(args)-1]; offset != 0 {
+				t.Errorf("offset with cursor = %v, want 0", offset)
+			}
+		})
+	}
+}
+
+func TestProfileSearchAgeKeyArgs(t *testing.T) {
+	search := newProfileSearch(repositories.SearchFilters{Sort: repositories.SearchSortAge}, searchTestToday)
+	_, args, err := search.selectPage(10, 0, nil)
+	if err != nil {
+		t.Fatalf("selectPage() error = %v", err)
+	}
+
+	if got := args[2]; got != birthDateParam(searchTestToday) {
+		t.Errorf("age date arg = %v, want %v", got, birthDateParam(searchTestToday))
+	}
+	if got := args[3]; got != math.MaxInt32 {
+		t.Errorf("hidden age arg = %v, want %d", got, math.MaxInt32)
+	}
+}
+
+func TestProfileSearchCursorRoundTrip(t *testing.T) {
+	query := "анна"
+	createdAt := time.Date(2026, time.October, 17, 12, 30, 45, 123456000, time.UTC)
+
+	tests := []struct {
+		name     string
+		filters  repositories.SearchFilters
+		keys     []interface{}
+		wantArgs []interface{}
+	}{
+		{
+			name:     "newest",
+			filters:  repositories.SearchFilters{Sort: repositories.SearchSortNewest},
+			keys:     []interface{}{createdAt, int64(42)},
+			wantArgs: []interface{}{createdAt, int64(42)},
+		},
+		{
+			name:     "name",
+			filters:  repositories.SearchFilters{Sort: repositories.SearchSortName},
+			keys:     []interface{}{"анна", "o'brien", int64(7)},
+			wantArgs: []interface{}{"анна", "o'brien", int64(7)},
+		},
+		{
+			name:     "age",
+			filters:  repositories.SearchFilters{Sort: repositories.SearchSortAge},
+			keys:     []interface{}{int64(math.MaxInt32), int64(1)},
+			wantArgs: []interface{}{int64(math.MaxInt32), int64(1)},
+		},
+		{
+			// Оценка хранится как real: курсор должен вернуть то же значение float32
+			name:     "relevance",
+			filters:  repositories.SearchFilters{Sort: repositories.SearchSortRelevance, Query: &query},
+			keys:     []interface{}{float32(0.1), createdAt, int64(3)},
+			wantArgs: []interface{}{float64(float32(0.1)), createdAt, int64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := newProfileSearch(tt.filters, searchTestToday)
+			dests := search.keyDests()
+			for i, key := range tt.keys {
+				reflect.ValueOf(dests[i]).Elem().Set(reflect.ValueOf(key))
+			}
+
+			decoded, err := repositories.DecodeSearchCursor(search.cursor(dests).Encode())
+			if err != nil {
+				t.Fatalf("DecodeSearchCursor() error = %v", err)
+			}
+
+			next := newProfileSearch(tt.filters, searchTestToday)
+			_, args, err := next.selectPage(10, 0, decoded)
+			if err != nil {
+				t.Fatalf("selectPage() error = %v", err)
+			}
+
+			// Значения курсора - последние параметры перед LIMIT и OFFSET
+			got := args[len(args)-2-len(tt.wantArgs) : len(args)-2]
+			for i := range got {
+				if wantTime, ok := tt.wantArgs[i].(time.Time); ok {
+					if gotTime, ok := got[i].(time.Time); !ok || !gotTime.Equal(wantTime) {
+						t.Errorf("key %d = %v, want %v", i, got[i], wantTime)
+					}
+					continue
+				}
+				if got[i] != tt.wantArgs[i] {
+					t.Errorf("key %d = %#v, want %#v", i, got[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProfileSearchRejectsInvalidCursor(t *testing.T) {
+	newest := repositories.SearchFilters{Sort: repositories.SearchSortNewest}
+	name := repositories.SearchFilters{Sort: repositories.SearchSortName}
+	age := repositories.SearchFilters{Sort: repositories.SearchSortAge}
+	relevance := repositories.SearchFilters{Sort: repositories.SearchSortRelevance}
+
+	tests := []struct {
+		name    string
+		filters repositories.SearchFilters
+		cursor  repositories.SearchCursor
+	}{
+		{
+			name:    "other sort",
+			filters: newest,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortName, Keys: []string{"a", "b", "1"}},
+		},
+		{
+			name:    "relevance cursor without query",
+			filters: relevance,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortRelevance, Keys: []string{"0.5", "2026-10-17T12:30:45Z", "1"}},
+		},
+		{
+			name:    "too few keys",
+			filters: name,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortName, Keys: []string{"a", "1"}},
+		},
+		{
+			name:    "too many keys",
+			filters: newest,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortNewest, Keys: []string{"2026-10-17T12:30:45Z", "1", "2"}},
+		},
+		{
+			name:    "invalid time",
+			filters: newest,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortNewest, Keys: []string{"yesterday", "1"}},
+		},
+		{
+			name:    "invalid id",
+			filters: newest,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortNewest, Keys: []string{"2026-10-17T12:30:45Z", "1 OR 1=1"}},
+		},
+		{
+			name:    "id out of range",
+			filters: newest,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortNewest, Keys: []string{"2026-10-17T12:30:45Z", fmt.Sprint(int64(math.MaxInt32) + 1)}},
+		},
+		{
+			name:    "invalid age",
+			filters: age,
+			cursor:  repositories.SearchCursor{Sort: repositories.SearchSortAge, Keys: []string{"twenty", "1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := newProfileSearch(tt.filters, searchTestToday)
+			if _, _, err := search.selectPage(10, 0, &tt.cursor); !errors.Is(err, repositories.ErrInvalidSearchCursor) {
+				t.Errorf("selectPage() error = %v, want ErrInvalidSearchCursor", err)
+			}
+		})
+	}
+}
+
+// searchTestKeys возвращает приемники ключей сортировки, заполненные значениями нужных типов
+func searchTestKeys(search *profileSearch) []interface{} {
+	dests := search.keyDests()
+	for _, dest := range dests {
+		switch value := dest.(type) {
+		case *int64:
+			*value = 42
+		case *float32:
+			*value = 0.5
+		case *time.Time:
+			*value = time.Date(2026, time.October, 17, 12, 30, 45, 0, time.UTC)
+		case *string:
+			*value = "анна"
+		}
+	}
+	return dests
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type refreshTokenRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewRefreshTokenRepository создает новый экземпляр репозитория refresh токенов
+func NewRefreshTokenRepository(db *sql.DB) repositories.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет новый refresh токен
+func (r *refreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) (*entities.RefreshToken, error) {
+	sqlcToken, err := r.queries.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		UserID:    int32(token.UserID),
+		FamilyID:  token.FamilyID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return r.convertToEntity(sqlcToken), nil
+}
+
+// GetByHash получает refresh токен по хешу
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	sqlcToken, err := r.queries.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return r.convertToEntity(sqlcToken), nil
+}
+
+// Revoke отзывает токен и возвращает false, если он уже был отозван ранее
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id int) (bool, error) {
+	rows, err := r.queries.RevokeRefreshToken(ctx, int32(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// RevokeFamily отзывает все токены цепочки ротаций
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	if err := r.queries.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser отзывает все refresh токены пользователя
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	if err := r.queries.RevokeUserRefreshTokens(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет истекшие refresh токены
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	if err := r.queries.DeleteExpiredRefreshTokens(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *refreshTokenRepository) convertToEntity(sqlcToken sqlc.RefreshToken) *entities.RefreshToken {
+	var revokedAt *time.Time
+	if sqlcToken.RevokedAt.Valid {
+		revokedAt = &sqlcToken.RevokedAt.Time
+	}
+
+	return &entities.RefreshToken{
+		ID:        int(sqlcToken.ID),
+		UserID:    int(sqlcToken.UserID),
+		FamilyID:  sqlcToken.FamilyID,
+		TokenHash: sqlcToken.TokenHash,
+		ExpiresAt: sqlcToken.ExpiresAt,
+		RevokedAt: revokedAt,
+		CreatedAt: sqlcToken.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type revocationStore struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewRevocationStore создает хранилище отозванных токенов в PostgreSQL
+func NewRevocationStore(db *sql.DB) repositories.RevocationStore {
+	return &revocationStore{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// RevokeToken отзывает токен или сессию по идентификатору
+func (s *revocationStore) RevokeToken(ctx context.Context, tokenID string, userID int, expiresAt time.Time) error {
+	err := s.queries.RevokeToken(ctx, sqlc.RevokeTokenParams{
+		TokenID:   tokenID,
+		UserID:    int32(userID),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserTokens отзывает все токены пользователя, выпущенные раньше issuedBefore
+func (s *revocationStore) RevokeUserTokens(ctx context.Context, userID int, issuedBefore time.Time) error {
+	err := s.queries.RevokeUserTokens(ctx, sqlc.RevokeUserTokensParams{
+		UserID:        int32(userID),
+		RevokedBefore: issuedBefore,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked проверяет, отозван ли токен
+func (s *revocationStore) IsRevoked(ctx context.Context, tokenIDs []string, userID int, issuedAt time.Time) (bool, error) {
+	revoked, err := s.queries.IsTokenRevoked(ctx, sqlc.IsTokenRevokedParams{
+		TokenIds: tokenIDs,
+		UserID:   int32(userID),
+		IssuedAt: issuedAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// DeleteExpired удаляет отзывы истекших токенов
+func (s *revocationStore) DeleteExpired(ctx context.Context, before time.Time) error {
+	if err := s.queries.DeleteExpiredRevokedTokens(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete expired revoked tokens: %w", err)
+	}
+
+	return nil
+}
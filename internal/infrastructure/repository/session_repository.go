@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type sessionRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewSessionRepository создает новый экземпляр репозитория сессий
+func NewSessionRepository(db *sql.DB) repositories.SessionRepository {
+	return &sessionRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create сохраняет новую сессию
+func (r *sessionRepository) Create(ctx context.Context, session *entities.Session) (*entities.Session, error) {
+	sqlcSession, err := r.queries.CreateSession(ctx, sqlc.CreateSessionParams{
+		ID:        session.ID,
+		UserID:    int32(session.UserID),
+		Device:    session.Device,
+		UserAgent: session.UserAgent,
+		IP:        session.IP,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return r.convertToEntity(sqlcSession), nil
+}
+
+// ListActiveByUser возвращает действующие сессии пользователя
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID int) ([]*entities.Session, error) {
+	sqlcSessions, err := r.queries.ListUserSessions(ctx, int32(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]*entities.Session, len(sqlcSessions))
+	for i, sqlcSession := range sqlcSessions {
+		sessions[i] = r.convertToEntity(sqlcSession)
+	}
+
+	return sessions, nil
+}
+
+// Touch отмечает активность в сессии
+func (r *sessionRepository) Touch(ctx context.Context, id, ip string, expiresAt time.Time) error {
+	err := r.queries.TouchSession(ctx, sqlc.TouchSessionParams{
+		ID:        id,
+		IP:        ip,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke завершает сессию пользователя
+func (r *sessionRepository) Revoke(ctx context.Context, id string, userID int) (bool, error) {
+	rows, err := r.queries.RevokeSession(ctx, sqlc.RevokeSessionParams{
+		ID:     id,
+		UserID: int32(userID),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// RevokeAllForUser завершает все сессии пользователя
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	if err := r.queries.RevokeUserSessions(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStale удаляет истекшие и завершенные сессии
+func (r *sessionRepository) DeleteStale(ctx context.Context, before time.Time) error {
+	if err := r.queries.DeleteStaleSessions(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete stale sessions: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *sessionRepository) convertToEntity(sqlcSession sqlc.Session) *entities.Session {
+	var revokedAt *time.Time
+	if sqlcSession.RevokedAt.Valid {
+		revokedAt = &sqlcSession.RevokedAt.Time
+	}
+
+	return &entities.Session{
+		ID:         sqlcSession.ID,
+		UserID:     int(sqlcSession.UserID),
+		Device:     sqlcSession.Device,
+		UserAgent:  sqlcSession.UserAgent,
+		IP:         sqlcSession.IP,
+		ExpiresAt:  sqlcSession.ExpiresAt,
+		LastSeenAt: sqlcSession.LastSeenAt,
+		RevokedAt:  revokedAt,
+		CreatedAt:  sqlcSession.CreatedAt,
+	}
+}
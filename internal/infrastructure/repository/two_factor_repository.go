@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type twoFactorRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewTwoFactorRepository создает новый экземпляр репозитория настроек 2FA
+func NewTwoFactorRepository(db *sql.DB) repositories.TwoFactorRepository {
+	return &twoFactorRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// GetByUserID получает настройки 2FA пользователя. Возвращает nil, если 2FA не настраивалась
+func (r *twoFactorRepository) GetByUserID(ctx context.Context, userID int) (*entities.TwoFactor, error) {
+	sqlcTOTP, err := r.queries.GetUserTOTP(ctx, int32(userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get two factor settings: %w", err)
+	}
+
+	return r.convertToEntity(sqlcTOTP), nil
+}
+
+// SaveSecret сохраняет новый неподтвержденный секрет
+func (r *twoFactorRepository) SaveSecret(ctx context.Context, userID int, secret string) (*entities.TwoFactor, error) {
+	sqlcTOTP, err := r.queries.UpsertUserTOTP(ctx, sqlc.UpsertUserTOTPParams{
+		UserID: int32(userID),
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save two factor secret: %w", err)
+	}
+
+	return r.convertToEntity(sqlcTOTP), nil
+}
+
+// Enable подтверждает 2FA и сохраняет коды восстановления в одной транзакции
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	rows, err := qtx.ConfirmUserTOTP(ctx, sqlc.ConfirmUserTOTPParams{
+		UserID:       int32(userID),
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm two factor: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if err := qtx.DeleteUserRecoveryCodes(ctx, int32(userID)); err != nil {
+		return false, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		err := qtx.CreateUserRecoveryCode(ctx, sqlc.CreateUserRecoveryCodeParams{
+			UserID:   int32(userID),
+			CodeHash: codeHash,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// UseStep отмечает использование временного шага TOTP
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	rows, err := r.queries.UseUserTOTPStep(ctx, sqlc.UseUserTOTPStepParams{
+		UserID:       int32(userID),
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to use totp step: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// UseRecoveryCode отмечает код восстановления использованным
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	rows, err := r.queries.UseUserRecoveryCode(ctx, sqlc.UseUserRecoveryCodeParams{
+		UserID:   int32(userID),
+		CodeHash: codeHash,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Disable отключает 2FA и удаляет коды восстановления
+func (r *twoFactorRepository) Disable(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.queries.WithTx(tx)
+
+	if err := qtx.DeleteUserRecoveryCodes(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if err := qtx.DeleteUserTOTP(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to delete two factor settings: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *twoFactorRepository) convertToEntity(sqlcTOTP sqlc.UserTotp) *entities.TwoFactor {
+	var confirmedAt *time.Time
+	if sqlcTOTP.ConfirmedAt.Valid {
+		confirmedAt = &sqlcTOTP.ConfirmedAt.Time
+	}
+
+	return &entities.TwoFactor{
+		UserID:       int(sqlcTOTP.UserID),
+		Secret:       sqlcTOTP.Secret,
+		ConfirmedAt:  confirmedAt,
+		LastUsedStep: sqlcTOTP.LastUsedStep,
+		CreatedAt:    sqlcTOTP.CreatedAt,
+	}
+}
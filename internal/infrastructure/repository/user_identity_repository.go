@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/infrastructure/database/sqlc"
+)
+
+type userIdentityRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+// NewUserIdentityRepository создает новый экземпляр репозитория внешних учетных записей
+func NewUserIdentityRepository(db *sql.DB) repositories.UserIdentityRepository {
+	return &userIdentityRepository{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Create привязывает внешнюю учетную запись к пользователю
+func (r *userIdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) (*entities.UserIdentity, error) {
+	sqlcIdentity, err := r.queries.CreateUserIdentity(ctx, sqlc.CreateUserIdentityParams{
+		UserID:   int32(identity.UserID),
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	return r.convertToEntity(sqlcIdentity), nil
+}
+
+// GetByProviderSubject получает привязку по провайдеру и идентификатору у провайдера
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	sqlcIdentity, err := r.queries.GetUserIdentity(ctx, sqlc.GetUserIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	return r.convertToEntity(sqlcIdentity), nil
+}
+
+// ListByUser возвращает все привязки пользователя
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID int) ([]*entities.UserIdentity, error) {
+	sqlcIdentities, err := r.queries.ListUserIdentities(ctx, int32(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+
+	identities := make([]*entities.UserIdentity, len(sqlcIdentities))
+	for i, sqlcIdentity := range sqlcIdentities {
+		identities[i] = r.convertToEntity(sqlcIdentity)
+	}
+
+	return identities, nil
+}
+
+// convertToEntity конвертирует sqlc модель в доменную сущность
+func (r *userIdentityRepository) convertToEntity(sqlcIdentity sqlc.UserIdentity) *entities.UserIdentity {
+	return &entities.UserIdentity{
+		ID:        int(sqlcIdentity.ID),
+		UserID:    int(sqlcIdentity.UserID),
+		Provider:  sqlcIdentity.Provider,
+		Subject:   sqlcIdentity.Subject,
+		Email:     sqlcIdentity.Email,
+		CreatedAt: sqlcIdentity.CreatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Spoloborota/experiment/internal/domain/entities"
 	"github.com/Spoloborota/experiment/internal/domain/repositories"
@@ -30,6 +31,9 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) (*enti
 		PasswordHash: user.PasswordHash,
 	})
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, repositories.ErrEmailAlreadyExists
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -41,7 +45,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int) (*entities.User, e
 	sqlcUser, err := r.queries.GetUserByID(ctx, int32(id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, repositories.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
@@ -54,7 +58,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entitie
 	sqlcUser, err := r.queries.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, repositories.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
@@ -62,13 +66,151 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entitie
 	return r.convertToEntity(sqlcUser), nil
 }
 
+// Update обновляет учетные данные пользователя
+func (r *userRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	sqlcUser, err := r.queries.UpdateUser(ctx, sqlc.UpdateUserParams{
+		ID:           int32(user.ID),
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		EmailVerifiedAt: sql.NullTime{
+			Time:  derefTime(user.EmailVerifiedAt),
+			Valid: user.EmailVerifiedAt != nil,
+		},
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, repositories.ErrEmailAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return r.convertToEntity(sqlcUser), nil
+}
+
+// MarkEmailVerified отмечает email подтвержденным, если адрес пользователя не изменился
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id int, email string) (bool, error) {
+	rows, err := r.queries.MarkUserEmailVerified(ctx, sqlc.MarkUserEmailVerifiedParams{
+		ID:    int32(id),
+		Email: email,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// SoftDelete отмечает пользователя удаленным
+func (r *userRepository) SoftDelete(ctx context.Context, id int) (bool, error) {
+	rows, err := r.queries.SoftDeleteUser(ctx, int32(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to soft delete user: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Delete окончательно удаляет пользователя; связанные данные удаляются каскадно
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	if err := r.queries.DeleteUser(ctx, int32(id)); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeDeleted окончательно удаляет пользователей, отмеченных удаленными раньше before
+func (r *userRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	rows, err := r.queries.PurgeDeletedUsers(ctx, sql.NullTime{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+
+	return rows, nil
+}
+
+// List возвращает страницу пользователей, упорядоченных по ID
+func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	sqlcUsers, err := r.queries.ListUsers(ctx, sqlc.ListUsersParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	users := make([]*entities.User, len(sqlcUsers))
+	for i, sqlcUser := range sqlcUsers {
+		users[i] = r.convertToEntity(sqlcUser)
+	}
+
+	return users, nil
+}
+
+// Count возвращает количество пользователей
+func (r *userRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.queries.CountUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// SetSuspended блокирует или разблокирует пользователя
+func (r *userRepository) SetSuspended(ctx context.Context, id int, suspended bool) (*entities.User, error) {
+	sqlcUser, err := r.queries.SetUserSuspended(ctx, sqlc.SetUserSuspendedParams{
+		Suspended: suspended,
+		ID:        int32(id),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to set user suspension: %w", err)
+	}
+
+	return r.convertToEntity(sqlcUser), nil
+}
+
 // convertToEntity конвертирует sqlc модель в доменную сущность
 func (r *userRepository) convertToEntity(sqlcUser sqlc.User) *entities.User {
+	var emailVerifiedAt *time.Time
+	if sqlcUser.EmailVerifiedAt.Valid {
+		emailVerifiedAt = &sqlcUser.EmailVerifiedAt.Time
+	}
+
+	var deletedAt *time.Time
+	if sqlcUser.DeletedAt.Valid {
+		deletedAt = &sqlcUser.DeletedAt.Time
+	}
+
+	var suspendedAt *time.Time
+	if sqlcUser.SuspendedAt.Valid {
+		suspendedAt = &sqlcUser.SuspendedAt.Time
+	}
+
 	return &entities.User{
-		ID:           int(sqlcUser.ID),
-		Email:        sqlcUser.Email,
-		PasswordHash: sqlcUser.PasswordHash,
-		CreatedAt:    sqlcUser.CreatedAt,
-		UpdatedAt:    sqlcUser.UpdatedAt,
+		ID:              int(sqlcUser.ID),
+		Email:           sqlcUser.Email,
+		PasswordHash:    sqlcUser.PasswordHash,
+		EmailVerifiedAt: emailVerifiedAt,
+		DeletedAt:       deletedAt,
+		Roles:           sqlcUser.Roles,
+		SuspendedAt:     suspendedAt,
+		CreatedAt:       sqlcUser.CreatedAt,
+		UpdatedAt:       sqlcUser.UpdatedAt,
+	}
+}
+
+// derefTime возвращает значение времени или нулевое время для nil
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
 	}
+	return *t
 }
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type AccountHandler struct {
+	accountService      *services.AccountService
+	verificationService *services.EmailVerificationService
+	logger              *zap.Logger
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+func NewAccountHandler(accountService *services.AccountService, verificationService *services.EmailVerificationService, logger *zap.Logger) *AccountHandler {
+	return &AccountHandler{
+		accountService:      accountService,
+		verificationService: verificationService,
+		logger:              logger,
+	}
+}
+
+// ChangePassword godoc
+// @Summary Смена пароля
+// @Description Меняет пароль после проверки текущего. Все сессии пользователя завершаются, в ответе новая пара токенов для текущего клиента
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param request body ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/password [put]
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CurrentPassword == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.accountService.ChangePassword(r.Context(), user.UserID, req.CurrentPassword, req.NewPassword, clientInfo(r))
+	if err != nil {
+		h.handleCredentialsError(w, r, user.Email, "Failed to change password", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newAuthResponse(tokens, nil))
+}
+
+// ChangeEmail godoc
+// @Summary Смена email
+// @Description Меняет email после проверки пароля. Новый адрес нужно подтвердить по ссылке из письма
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param request body ChangeEmailRequest true "Новый email и текущий пароль"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/email [put]
+func (h *AccountHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updatedUser, err := h.accountService.ChangeEmail(r.Context(), user.UserID, req.Password, req.Email, middleware.ClientIP(r))
+	if err != nil {
+		h.handleCredentialsError(w, r, user.Email, "Failed to change email", err)
+		return
+	}
+
+	// Отправляем письмо на новый адрес; при ошибке пользователь может запросить его повторно
+	if err := h.verificationService.SendVerification(r.Context(), updatedUser); err != nil {
+		h.logger.Error("Failed to send verification email", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedUser)
+}
+
+// DeleteAccount godoc
+// @Summary Удаление аккаунта
+// @Description Удаляет аккаунт после проверки пароля и завершает все сессии. Аккаунт и анкета сразу скрываются, а данные окончательно удаляются после окончания срока хранения
+// @Tags account
+// @Accept json
+// @Param request body DeleteAccountRequest true "Текущий пароль"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account [delete]
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(r.Context(), user.UserID, req.Password, middleware.ClientIP(r)); err != nil {
+		h.handleCredentialsError(w, r, user.Email, "Failed to delete account", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportData godoc
+// @Summary Экспорт данных аккаунта
+// @Description Возвращает JSON архив со всеми данными, которые хранятся о пользователе
+// @Tags account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/export [get]
+func (h *AccountHandler) ExportData(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := h.accountService.ExportData(r.Context(), user.UserID)
+	if err != nil {
+		h.logger.Error("Failed to export account data", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to export account data", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%d-export.json"`, user.UserID))
+	json.NewEncoder(w).Encode(export)
+}
+
+// handleCredentialsError отвечает на ошибки изменения учетных данных
+func (h *AccountHandler) handleCredentialsError(w http.ResponseWriter, r *http.Request, email, message string, err error) {
+	var throttled *services.LoginThrottledError
+	var policyErr *services.PasswordPolicyError
+	switch {
+	case errors.As(err, &throttled):
+		writeThrottledResponse(w, r, h.logger, email, throttled)
+	case errors.Is(err, services.ErrIncorrectPassword):
+		h.writeErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repositories.ErrEmailAlreadyExists):
+		h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &policyErr):
+		h.writeErrorResponse(w, policyErr.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrEmailUnchanged),
+		errors.Is(err, entities.ErrEmailRequired),
+		errors.Is(err, entities.ErrInvalidEmail):
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(message, zap.Error(err))
+		h.writeErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *AccountHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,423 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type AdminHandler struct {
+	adminService *services.AdminService
+	logger       *zap.Logger
+}
+
+type UsersResponse struct {
+	Users  []interface{} `json:"users"`
+	Total  int           `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason"` // Причина входа под пользователем, например номер обращения в поддержку
+}
+
+type ImpersonationResponse struct {
+	Token          string      `json:"token"`
+	ExpiresIn      int         `json:"expires_in"` // Время жизни токена в секундах
+	ImpersonatedBy int         `json:"impersonated_by"`
+	User           interface{} `json:"user"`
+}
+
+type AuditLogResponse struct {
+	Entries []interface{} `json:"entries"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
+
+func NewAdminHandler(adminService *services.AdminService, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		logger:       logger,
+	}
+}
+
+// ListUsers godoc
+// @Summary Список пользователей
+// @Description Возвращает пользователей с ролями и статусом блокировки. Доступно администраторам
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Лимит результатов" default(10)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} UsersResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/users [get]
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset := 10, 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if value, err := strconv.Atoi(limitStr); err == nil && value > 0 {
+			limit = value
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if value, err := strconv.Atoi(offsetStr); err == nil && value >= 0 {
+			offset = value
+		}
+	}
+
+	users, total, err := h.adminService.ListUsers(r.Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list users", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to list users", http.StatusInternalServerError)
+		return
+	}
+
+	usersInterface := make([]interface{}, len(users))
+	for i, user := range users {
+		usersInterface[i] = user
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(UsersResponse{
+		Users:  usersInterface,
+		Total:  total,
+		Limit:  min(limit, 100),
+		Offset: offset,
+	})
+}
+
+// SuspendUser godoc
+// @Summary Блокировка пользователя
+// @Description Блокирует пользователя и завершает все его сессии. Доступно администраторам
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	admin, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.adminService.SuspendUser(r.Context(), admin.UserID, id)
+	if err != nil {
+		h.handleError(w, "Failed to suspend user", err)
+		return
+	}
+
+	h.logger.Info("User suspended", zap.Int("user_id", id), zap.Int("admin_id", admin.UserID))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UnsuspendUser godoc
+// @Summary Снятие блокировки пользователя
+// @Description Снимает блокировку с пользователя. Доступно администраторам
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id}/unsuspend [post]
+func (h *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	admin, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.adminService.UnsuspendUser(r.Context(), id)
+	if err != nil {
+		h.handleError(w, "Failed to unsuspend user", err)
+		return
+	}
+
+	h.logger.Info("User unsuspended", zap.Int("user_id", id), zap.Int("admin_id", admin.UserID))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateProfile godoc
+// @Summary Редактирование любой анкеты
+// @Description Обновляет анкету по ID, в том числе скрытую. Доступно администраторам и модераторам
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID профиля"
+// @Param request body UpdateProfileRequest true "Данные для обновления профиля"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/profiles/{id} [put]
+func (h *AdminHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid profile ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.adminService.UpdateProfile(
+		r.Context(),
+		id,
+		req.FirstName,
+		req.LastName,
+		req.BirthDate,
+		req.Gender,
+		req.City,
+		req.Interests,
+	)
+	if err != nil {
+		h.handleError(w, "Failed to update profile", err)
+		return
+	}
+
+	h.logger.Info("Profile updated by moderator", zap.Int("profile_id", id), zap.Int("moderator_id", moderator.UserID))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// HideProfile godoc
+// @Summary Скрытие анкеты
+// @Description Скрывает анкету из поиска и просмотра по ссылке. Доступно администраторам и модераторам
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID профиля"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/profiles/{id}/hide [post]
+func (h *AdminHandler) HideProfile(w http.ResponseWriter, r *http.Request) {
+	h.setProfileHidden(w, r, true)
+}
+
+// UnhideProfile godoc
+// @Summary Возврат анкеты в поиск
+// @Description Снова делает скрытую анкету видимой. Доступно администраторам и модераторам
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID профиля"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/profiles/{id}/unhide [post]
+func (h *AdminHandler) UnhideProfile(w http.ResponseWriter, r *http.Request) {
+	h.setProfileHidden(w, r, false)
+}
+
+// setProfileHidden меняет видимость анкеты из параметра пути
+func (h *AdminHandler) setProfileHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	moderator, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid profile ID", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.adminService.SetProfileHidden(r.Context(), id, hidden)
+	if err != nil {
+		h.handleError(w, "Failed to change profile visibility", err)
+		return
+	}
+
+	h.logger.Info("Profile visibility changed by moderator",
+		zap.Int("profile_id", id),
+		zap.Bool("hidden", hidden),
+		zap.Int("moderator_id", moderator.UserID),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// Impersonate godoc
+// @Summary Вход под пользователем
+// @Description Выдает короткоживущий access токен пользователя для воспроизведения проблем. Refresh токен не выдается.
+// @Description Ответы на запросы с этим токеном содержат заголовок X-Impersonated-By, каждый запрос записывается в журнал аудита.
+// @Description Смена пароля и email, удаление аккаунта, экспорт данных, 2FA, API ключи и администрирование с таким токеном недоступны.
+// @Description Доступно администраторам
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param request body ImpersonateRequest true "Причина входа под пользователем"
+// @Success 200 {object} ImpersonationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id}/impersonate [post]
+func (h *AdminHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	admin, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tokens, user, err := h.adminService.Impersonate(r.Context(), admin.UserID, id, req.Reason, clientInfo(r))
+	if err != nil {
+		h.handleError(w, "Failed to impersonate user", err)
+		return
+	}
+
+	h.logger.Info("Impersonation started",
+		zap.Int("user_id", id),
+		zap.Int("admin_id", admin.UserID),
+		zap.String("reason", req.Reason),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ImpersonationResponse{
+		Token:          tokens.AccessToken,
+		ExpiresIn:      int(tokens.ExpiresIn.Seconds()),
+		ImpersonatedBy: admin.UserID,
+		User:           user,
+	})
+}
+
+// ListAuditLog godoc
+// @Summary Журнал действий от имени пользователей
+// @Description Возвращает выдачи токенов входа под пользователем и запросы, выполненные с ними, новые первыми. Доступно администраторам
+// @Tags admin
+// @Produce json
+// @Param actor_id query int false "ID администратора"
+// @Param user_id query int false "ID пользователя"
+// @Param limit query int false "Лимит результатов" default(50)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} AuditLogResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/audit-log [get]
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	filter := repositories.AuditLogFilter{Limit: 50}
+	query := r.URL.Query()
+	if value, err := strconv.Atoi(query.Get("actor_id")); err == nil && value > 0 {
+		filter.ActorID = value
+	}
+	if value, err := strconv.Atoi(query.Get("user_id")); err == nil && value > 0 {
+		filter.UserID = value
+	}
+	if value, err := strconv.Atoi(query.Get("limit")); err == nil && value > 0 {
+		filter.Limit = value
+	}
+	if value, err := strconv.Atoi(query.Get("offset")); err == nil && value >= 0 {
+		filter.Offset = value
+	}
+
+	entries, err := h.adminService.ListAuditLog(r.Context(), filter)
+	if err != nil {
+		h.logger.Error("Failed to list audit log", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to list audit log", http.StatusInternalServerError)
+		return
+	}
+
+	entriesInterface := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		entriesInterface[i] = entry
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AuditLogResponse{
+		Entries: entriesInterface,
+		Limit:   min(filter.Limit, 200),
+		Offset:  filter.Offset,
+	})
+}
+
+// handleError отвечает на ошибки административных операций
+func (h *AdminHandler) handleError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrUserNotFound), errors.Is(err, repositories.ErrProfileNotFound):
+		h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrCannotSuspendSelf),
+		errors.Is(err, services.ErrCannotImpersonateSelf),
+		errors.Is(err, services.ErrImpersonationReasonNeeded):
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrCannotImpersonateAdmin), errors.Is(err, services.ErrAccountSuspended):
+		h.writeErrorResponse(w, err.Error(), http.StatusForbidden)
+	default:
+		h.logger.Error(message, zap.Error(err))
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func (h *AdminHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+	logger        *zap.Logger
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // RFC 3339, без срока действия если не указан
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string      `json:"key"` // Значение ключа, показывается только один раз
+	APIKey interface{} `json:"api_key"`
+}
+
+type APIKeysResponse struct {
+	APIKeys []interface{} `json:"api_keys"`
+}
+
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Создание API ключа
+// @Description Создает персональный API ключ с указанными областями доступа (profiles:read, profiles:write). Значение ключа показывается только один раз
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body CreateAPIKeyRequest true "Название, области доступа и срок действия ключа"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	apiKey, rawKey, err := h.apiKeyService.Create(r.Context(), user.UserID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyAPIKeys) {
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to create API key", zap.Error(err))
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Info("API key created", zap.Int("user_id", user.UserID), zap.Int("api_key_id", apiKey.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPIKeyResponse{
+		Key:    rawKey,
+		APIKey: apiKey,
+	})
+}
+
+// ListAPIKeys godoc
+// @Summary Список API ключей
+// @Description Возвращает действующие API ключи пользователя без их значений
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} APIKeysResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := h.apiKeyService.List(r.Context(), user.UserID)
+	if err != nil {
+		h.logger.Error("Failed to list API keys", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to list API keys", http.StatusInternalServerError)
+		return
+	}
+
+	keysInterface := make([]interface{}, len(keys))
+	for i, key := range keys {
+		keysInterface[i] = key
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(APIKeysResponse{APIKeys: keysInterface})
+}
+
+// RevokeAPIKey godoc
+// @Summary Отзыв API ключа
+// @Description Отзывает API ключ, после чего запросы с ним отклоняются
+// @Tags api-keys
+// @Param id path int true "ID ключа"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(r.Context(), user.UserID, id); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to revoke API key", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to revoke API key", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("API key revoked", zap.Int("user_id", user.UserID), zap.Int("api_key_id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIKeyHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -2,15 +2,18 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
 	"go.uber.org/zap"
 )
 
 type AuthHandler struct {
-	authService *services.AuthService
-	logger      *zap.Logger
+	authService         *services.AuthService
+	verificationService *services.EmailVerificationService
+	logger              *zap.Logger
 }
 
 type RegisterRequest struct {
@@ -23,25 +26,43 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"` // Код из приложения-аутентификатора или код восстановления
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  interface{} `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int         `json:"expires_in"` // Время жизни access токена в секундах
+	User         interface{} `json:"user,omitempty"`
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"` // Время жизни вызова в секундах
 }
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
-func NewAuthHandler(authService *services.AuthService, logger *zap.Logger) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, verificationService *services.EmailVerificationService, logger *zap.Logger) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		logger:      logger,
+		authService:         authService,
+		verificationService: verificationService,
+		logger:              logger,
 	}
 }
 
 // Register godoc
 // @Summary Регистрация пользователя
-// @Description Создает нового пользователя в системе
+// @Description Создает нового пользователя в системе и отправляет письмо для подтверждения email
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -66,18 +87,20 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Генерируем токен
-	token, _, err := h.authService.Login(r.Context(), req.Email, req.Password)
-	if err != nil {
+	// Отправляем письмо для подтверждения email; при ошибке пользователь может запросить его повторно
+	if err := h.verificationService.SendVerification(r.Context(), user); err != nil {
+		h.logger.Error("Failed to send verification email", zap.Error(err))
+	}
+
+	// Генерируем токены
+	result, err := h.authService.Login(r.Context(), req.Email, req.Password, clientInfo(r))
+	if err != nil || result.Tokens == nil {
 		h.logger.Error("Failed to login after registration", zap.Error(err))
 		h.writeErrorResponse(w, "Registration successful but login failed", http.StatusInternalServerError)
 		return
 	}
 
-	response := AuthResponse{
-		Token: token,
-		User:  user,
-	}
+	response := newAuthResponse(result.Tokens, user)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -86,14 +109,19 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login godoc
 // @Summary Авторизация пользователя
-// @Description Авторизует пользователя и возвращает JWT токен
+// @Description Авторизует пользователя и возвращает JWT токен. Если у пользователя включена 2FA, возвращает 202 с токеном вызова, который подтверждается через /api/v1/login/2fa
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Данные для авторизации"
 // @Success 200 {object} AuthResponse
+// @Success 202 {object} TwoFactorChallengeResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Header 429 {integer} Retry-After "Через сколько секунд можно повторить попытку"
 // @Router /api/v1/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
@@ -104,20 +132,180 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Авторизуемся
-	token, user, err := h.authService.Login(r.Context(), req.Email, req.Password)
+	result, err := h.authService.Login(r.Context(), req.Email, req.Password, clientInfo(r))
 	if err != nil {
-		h.logger.Error("Failed to login", zap.Error(err))
-		h.writeErrorResponse(w, err.Error(), http.StatusUnauthorized)
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			writeThrottledResponse(w, r, h.logger, req.Email, throttled)
+		case errors.Is(err, services.ErrInvalidCredentials):
+			h.writeErrorResponse(w, services.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+		case errors.Is(err, services.ErrAccountSuspended):
+			h.writeErrorResponse(w, services.ErrAccountSuspended.Error(), http.StatusForbidden)
+		default:
+			h.logger.Error("Failed to login", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to login", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+// LoginTwoFactor godoc
+// @Summary Подтверждение входа кодом 2FA
+// @Description Обменивает токен вызова, полученный при входе, и код из приложения-аутентификатора (или код восстановления) на пару токенов
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body LoginTwoFactorRequest true "Токен вызова и код"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Header 429 {integer} Retry-After "Через сколько секунд можно повторить попытку"
+// @Router /api/v1/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req LoginTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ChallengeToken == "" || req.Code == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	response := AuthResponse{
-		Token: token,
-		User:  user,
+	tokens, user, err := h.authService.CompleteTwoFactorLogin(r.Context(), req.ChallengeToken, req.Code, clientInfo(r))
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			writeThrottledResponse(w, r, h.logger, "", throttled)
+		case errors.Is(err, services.ErrInvalidTwoFactorChallenge):
+			h.writeErrorResponse(w, services.ErrInvalidTwoFactorChallenge.Error(), http.StatusUnauthorized)
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			h.writeErrorResponse(w, services.ErrInvalidTwoFactorCode.Error(), http.StatusUnauthorized)
+		case errors.Is(err, services.ErrAccountSuspended):
+			h.writeErrorResponse(w, services.ErrAccountSuspended.Error(), http.StatusForbidden)
+		default:
+			h.logger.Error("Failed to complete two-factor login", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to login", http.StatusInternalServerError)
+		}
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(newAuthResponse(tokens, user))
+}
+
+// RefreshToken godoc
+// @Summary Обновление токенов
+// @Description Обменивает refresh токен на новую пару токенов. Каждый refresh токен одноразовый; повторное использование отзывает все токены этого входа
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh токен"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/token/refresh [post]
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken, clientInfo(r))
+	if err != nil {
+		if errors.Is(err, services.ErrRefreshTokenReused) {
+			h.logger.Warn("Refresh token reuse detected, token family revoked")
+		} else if !errors.Is(err, services.ErrInvalidRefreshToken) && !errors.Is(err, services.ErrAccountSuspended) {
+			h.logger.Error("Failed to refresh token", zap.Error(err))
+		}
+		h.writeErrorResponse(w, services.ErrInvalidRefreshToken.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newAuthResponse(tokens, nil))
+}
+
+// Logout godoc
+// @Summary Выход из системы
+// @Description Завершает текущую сессию: access токен и refresh токены этого входа перестают действовать
+// @Tags auth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), user); err != nil {
+		h.logger.Error("Failed to logout", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to logout", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Выход со всех устройств
+// @Description Отзывает все токены пользователя, включая текущий
+// @Tags auth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/logout-all [post]
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.LogoutAll(r.Context(), user.UserID); err != nil {
+		h.logger.Error("Failed to logout from all sessions", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to logout", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeLoginResult отвечает на успешный вход парой токенов или, если включена 2FA, вызовом второго фактора
+func writeLoginResult(w http.ResponseWriter, result *services.LoginResult) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Включена 2FA: токены выдаются только после подтверждения кода
+	if result.Challenge != nil {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.Challenge.Token,
+			ExpiresIn:         int(result.Challenge.ExpiresIn.Seconds()),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(newAuthResponse(result.Tokens, result.User))
+}
+
+// newAuthResponse формирует ответ с парой токенов
+func newAuthResponse(tokens *services.TokenPair, user interface{}) AuthResponse {
+	return AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+		User:         user,
+	}
 }
 
 func (h *AuthHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
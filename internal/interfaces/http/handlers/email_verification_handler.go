@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type EmailVerificationHandler struct {
+	verificationService *services.EmailVerificationService
+	logger              *zap.Logger
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func NewEmailVerificationHandler(verificationService *services.EmailVerificationService, logger *zap.Logger) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		verificationService: verificationService,
+		logger:              logger,
+	}
+}
+
+// VerifyEmail godoc
+// @Summary Подтверждение email
+// @Description Подтверждает владение email по токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Токен из письма"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/email/verify [post]
+func (h *EmailVerificationHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verificationService.Verify(r.Context(), req.Token); err != nil {
+		if !errors.Is(err, services.ErrInvalidVerificationToken) {
+			h.logger.Error("Failed to verify email", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to verify email", http.StatusInternalServerError)
+			return
+		}
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+// @Summary Повторная отправка письма подтверждения
+// @Description Отправляет новое письмо для подтверждения email текущего пользователя
+// @Tags auth
+// @Produce json
+// @Success 202 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/email/verify/resend [post]
+func (h *EmailVerificationHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.verificationService.ResendVerification(r.Context(), user.UserID); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to resend verification email", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to send verification email", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(MessageResponse{Message: "Verification email has been sent"})
+}
+
+func (h *EmailVerificationHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+// profileETag возвращает ETag анкеты. Версия меняется при каждом изменении анкеты, а возраст,
+// вычисляемый по дате рождения при чтении, - в день рождения без изменения версии
+func profileETag(profile *entities.Profile) string {
+	return `"` + profileETagValue(profile) + `"`
+}
+
+// profileViewETag возвращает ETag анкеты в том виде, в каком ее видит зритель. Владелец получает
+// обычный ETag, остальные - с отношением к владельцу, так как набор видимых полей у них разный
+func profileViewETag(profile *entities.Profile, relation entities.ViewerRelation) string {
+	if relation == entities.ViewerOwner {
+		return profileETag(profile)
+	}
+	return `"` + profileETagValue(profile) + "-" + relation.String() + `"`
+}
+
+// profileETagValue возвращает значение ETag без кавычек: версию и возраст, если указана дата рождения
+func profileETagValue(profile *entities.Profile) string {
+	value := strconv.Itoa(profile.Version)
+	if !profile.BirthDate.IsZero() {
+		value += "-" + strconv.Itoa(profile.Age)
+	}
+	return value
+}
+
+// ifMatchVersion возвращает версию анкеты из заголовка If-Match. 0 означает, что условия нет
+// (заголовок отсутствует или равен "*"). ok равен false, если значение не может совпасть
+// ни с одной версией: слабый ETag, список из нескольких ETag или неизвестный формат.
+// Возраст в ETag не проверяется: изменения конфликтуют, только если анкету изменили после чтения
+func ifMatchVersion(r *http.Request) (version int, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	if len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, false
+	}
+
+	value, age, hasAge := strings.Cut(header[1:len(header)-1], "-")
+	if hasAge {
+		if _, err := strconv.Atoi(age); err != nil {
+			return 0, false
+		}
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// ifNoneMatch проверяет заголовок If-None-Match. ETag сравниваются слабым сравнением (RFC 9110)
+func ifNoneMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeNotModified отвечает 304, если у клиента актуальная версия анкеты, и возвращает true
+func writeNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	if !ifNoneMatch(r, etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+type InterestHandler struct {
+	interestService *services.InterestService
+	logger          *zap.Logger
+}
+
+type InterestsResponse struct {
+	Interests []interface{} `json:"interests"`
+}
+
+func NewInterestHandler(interestService *services.InterestService, logger *zap.Logger) *InterestHandler {
+	return &InterestHandler{
+		interestService: interestService,
+		logger:          logger,
+	}
+}
+
+// SuggestInterests godoc
+// @Summary Подсказки интересов
+// @Description Возвращает канонические интересы, название или синоним которых начинается с prefix, от самых популярных.
+// @Description Язык названий берется из параметра locale или заголовка Accept-Language, по умолчанию en
+// @Tags interests
+// @Produce json
+// @Param prefix query string false "Начало названия интереса"
+// @Param locale query string false "Язык названий (en, ru)"
+// @Param limit query int false "Лимит результатов" default(10)
+// @Success 200 {object} InterestsResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/interests [get]
+func (h *InterestHandler) SuggestInterests(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value <= 0 {
+			h.writeErrorResponse(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = value
+	}
+
+	locale := query.Get("locale")
+	if locale == "" {
+		locale = acceptLanguage(r)
+	}
+
+	interests, err := h.interestService.Suggest(r.Context(), query.Get("prefix"), locale, limit)
+	if err != nil {
+		h.logger.Error("Failed to suggest interests", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to suggest interests", http.StatusInternalServerError)
+		return
+	}
+
+	interestsInterface := make([]interface{}, len(interests))
+	for i, interest := range interests {
+		interestsInterface[i] = interest
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(InterestsResponse{Interests: interestsInterface})
+}
+
+// acceptLanguage возвращает основной язык первого значения заголовка Accept-Language,
+// например "ru" для "ru-RU,ru;q=0.9,en;q=0.8"
+func acceptLanguage(r *http.Request) string {
+	header := r.Header.Get("Accept-Language")
+	first, _, _ := strings.Cut(header, ",")
+	tag, _, _ := strings.Cut(first, ";")
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	if primary == "*" {
+		return ""
+	}
+	return primary
+}
+
+func (h *InterestHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+type OAuthHandler struct {
+	oauthService *services.OAuthService
+	logger       *zap.Logger
+}
+
+func NewOAuthHandler(oauthService *services.OAuthService, logger *zap.Logger) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+		logger:       logger,
+	}
+}
+
+// Authorize godoc
+// @Summary Вход через внешнего провайдера
+// @Description Перенаправляет на страницу входа OIDC провайдера (authorization code flow с PKCE)
+// @Tags auth
+// @Param provider path string true "Имя провайдера"
+// @Success 302
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/oauth/{provider}/authorize [get]
+func (h *OAuthHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	authURL, err := h.oauthService.Start(r.Context(), provider)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownIdentityProvider) {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to start OAuth sign-in", zap.String("provider", provider), zap.Error(err))
+		h.writeErrorResponse(w, "Identity provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback godoc
+// @Summary Завершение входа через внешнего провайдера
+// @Description Обрабатывает возврат со страницы входа провайдера. Внешняя учетная запись привязывается к пользователю с тем же подтвержденным email или к новому пользователю. Ответ такой же, как у /api/v1/login
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Param code query string true "Код авторизации"
+// @Param state query string true "Значение state из запроса авторизации"
+// @Success 200 {object} AuthResponse
+// @Success 202 {object} TwoFactorChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	// Пользователь отказался от входа или провайдер вернул ошибку
+	if providerError := query.Get("error"); providerError != "" {
+		h.writeErrorResponse(w, "Identity provider returned an error: "+providerError, http.StatusBadRequest)
+		return
+	}
+
+	code, state := query.Get("code"), query.Get("state")
+	if code == "" || state == "" {
+		h.writeErrorResponse(w, "Missing code or state", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.oauthService.Complete(r.Context(), provider, state, code, clientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownIdentityProvider):
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidOAuthState), errors.Is(err, services.ErrOAuthEmailRequired):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrOAuthEmailNotVerified), errors.Is(err, services.ErrOAuthAccountNotVerified),
+			errors.Is(err, repositories.ErrEmailAlreadyExists):
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, services.ErrAccountSuspended):
+			h.writeErrorResponse(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, services.ErrInvalidCredentials):
+			h.writeErrorResponse(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, services.ErrOAuthExchangeFailed):
+			h.logger.Warn("OAuth code exchange failed", zap.String("provider", provider), zap.Error(err))
+			h.writeErrorResponse(w, services.ErrOAuthExchangeFailed.Error(), http.StatusBadGateway)
+		default:
+			h.logger.Error("Failed to complete OAuth sign-in", zap.String("provider", provider), zap.Error(err))
+			h.writeErrorResponse(w, "Failed to complete sign-in", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+func (h *OAuthHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
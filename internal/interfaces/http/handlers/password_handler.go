@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type PasswordHandler struct {
+	passwordResetService *services.PasswordResetService
+	logger               *zap.Logger
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+func NewPasswordHandler(passwordResetService *services.PasswordResetService, logger *zap.Logger) *PasswordHandler {
+	return &PasswordHandler{
+		passwordResetService: passwordResetService,
+		logger:               logger,
+	}
+}
+
+// ForgotPassword godoc
+// @Summary Запрос сброса пароля
+// @Description Отправляет на email ссылку для сброса пароля. Ответ не зависит от того, существует ли аккаунт
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Email пользователя"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /api/v1/password/forgot [post]
+func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Запрос обрабатывается в фоне, а ошибки только логируются: ни содержимое, ни время ответа
+	// не должны раскрывать существование аккаунта
+	if err := h.passwordResetService.EnqueueReset(r.Context(), req.Email, middleware.ClientIP(r)); err != nil {
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			writeThrottledResponse(w, r, h.logger, req.Email, throttled)
+			return
+		case errors.Is(err, services.ErrPasswordResetQueueFull):
+			h.logger.Warn("Password reset request dropped", zap.Error(err))
+		default:
+			h.logger.Error("Failed to request password reset", zap.Error(err))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(MessageResponse{Message: "If the account exists, a password reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по токену из письма и завершает все сессии пользователя
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Токен и новый пароль"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/password/reset [post]
+func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		var policyErr *services.PasswordPolicyError
+		switch {
+		case errors.Is(err, services.ErrInvalidResetToken), errors.As(err, &policyErr):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Error("Failed to reset password", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to reset password", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PasswordHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/repositories"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+// photoFormField - имя поля multipart формы с файлом фотографии
+const photoFormField = "photo"
+
+// multipartOverhead - запас на заголовки multipart формы сверх размера файла
+const multipartOverhead = 1 << 20
+
+var errInvalidMultipart = errors.New("request must be multipart/form-data with a photo field")
+
+type PhotoHandler struct {
+	photoService *services.PhotoService
+	logger       *zap.Logger
+}
+
+type PhotosResponse struct {
+	Photos []interface{} `json:"photos"`
+}
+
+type ReorderPhotosRequest struct {
+	PhotoIDs []int `json:"photo_ids"` // Все фотографии анкеты в новом порядке
+}
+
+func NewPhotoHandler(photoService *services.PhotoService, logger *zap.Logger) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+		logger:       logger,
+	}
+}
+
+// UploadPhoto godoc
+// @Summary Загрузка фотографии
+// @Description Добавляет фотографию в анкету текущего пользователя. Принимаются JPEG и PNG; метаданные удаляются,
+// @Description создаются уменьшенные копии small, medium и large. Первая фотография становится основной
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param photo formData file true "Файл изображения"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me/photos [post]
+func (h *PhotoHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	data, err := h.readPhoto(w, r)
+	if err != nil {
+		h.handleError(w, "Failed to read photo", err)
+		return
+	}
+
+	photo, err := h.photoService.Upload(r.Context(), user.UserID, data)
+	if err != nil {
+		h.handleError(w, "Failed to upload photo", err)
+		return
+	}
+
+	h.logger.Info("Photo uploaded", zap.Int("user_id", user.UserID), zap.Int("photo_id", photo.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(photo)
+}
+
+// ListPhotos godoc
+// @Summary Список фотографий
+// @Description Возвращает фотографии анкеты текущего пользователя в порядке показа
+// @Tags photos
+// @Produce json
+// @Success 200 {object} PhotosResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me/photos [get]
+func (h *PhotoHandler) ListPhotos(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	photos, err := h.photoService.List(r.Context(), user.UserID)
+	if err != nil {
+		h.handleError(w, "Failed to list photos", err)
+		return
+	}
+
+	h.writePhotos(w, photos)
+}
+
+// ReorderPhotos godoc
+// @Summary Порядок фотографий
+// @Description Задает порядок показа фотографий. Нужно перечислить все фотографии анкеты
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param request body ReorderPhotosRequest true "ID фотографий в новом порядке"
+// @Success 200 {object} PhotosResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me/photos/order [put]
+func (h *PhotoHandler) ReorderPhotos(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	photos, err := h.photoService.Reorder(r.Context(), user.UserID, req.PhotoIDs)
+	if err != nil {
+		h.handleError(w, "Failed to reorder photos", err)
+		return
+	}
+
+	h.writePhotos(w, photos)
+}
+
+// SetPrimaryPhoto godoc
+// @Summary Основная фотография
+// @Description Делает фотографию основной
+// @Tags photos
+// @Produce json
+// @Param id path int true "ID фотографии"
+// @Success 200 {object} PhotosResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me/photos/{id}/primary [post]
+func (h *PhotoHandler) SetPrimaryPhoto(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	photos, err := h.photoService.SetPrimary(r.Context(), user.UserID, id)
+	if err != nil {
+		h.handleError(w, "Failed to set primary photo", err)
+		return
+	}
+
+	h.writePhotos(w, photos)
+}
+
+// DeletePhoto godoc
+// @Summary Удаление фотографии
+// @Description Удаляет фотографию из анкеты. Если она была основной, основной становится первая из оставшихся
+// @Tags photos
+// @Param id path int true "ID фотографии"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me/photos/{id} [delete]
+func (h *PhotoHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.photoService.Delete(r.Context(), user.UserID, id); err != nil {
+		h.handleError(w, "Failed to delete photo", err)
+		return
+	}
+
+	h.logger.Info("Photo deleted", zap.Int("user_id", user.UserID), zap.Int("photo_id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readPhoto читает файл из поля photo multipart формы, не загружая в память больше допустимого размера.
+// Заявленный тип файла проверяется здесь, фактический - при обработке изображения
+func (h *PhotoHandler) readPhoto(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	maxSize := h.photoService.MaxSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errInvalidMultipart
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, services.ErrPhotoTooLarge
+			}
+			return nil, errInvalidMultipart
+		}
+
+		if part.FormName() != photoFormField {
+			part.Close()
+			continue
+		}
+		defer part.Close()
+
+		contentType := part.Header.Get("Content-Type")
+		if contentType != "image/jpeg" && contentType != "image/png" {
+			return nil, services.ErrUnsupportedImageType
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, services.ErrPhotoTooLarge
+			}
+			return nil, errInvalidMultipart
+		}
+		if int64(len(data)) > maxSize {
+			return nil, services.ErrPhotoTooLarge
+		}
+
+		return data, nil
+	}
+}
+
+// writePhotos отвечает списком фотографий
+func (h *PhotoHandler) writePhotos(w http.ResponseWriter, photos []*entities.ProfilePhoto) {
+	photosInterface := make([]interface{}, len(photos))
+	for i, photo := range photos {
+		photosInterface[i] = photo
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PhotosResponse{Photos: photosInterface})
+}
+
+// handleError отвечает на ошибки операций с фотографиями
+func (h *PhotoHandler) handleError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrProfileNotFound), errors.Is(err, repositories.ErrPhotoNotFound):
+		h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrPhotoTooLarge):
+		h.writeErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrUnsupportedImageType):
+		h.writeErrorResponse(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, services.ErrTooManyPhotos):
+		h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidImage),
+		errors.Is(err, services.ErrImageDimensions),
+		errors.Is(err, services.ErrInvalidPhotoOrder),
+		errors.Is(err, errInvalidMultipart):
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(message, zap.Error(err))
+		h.writeErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *PhotoHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/Spoloborota/experiment/internal/domain/entities"
 	"github.com/Spoloborota/experiment/internal/domain/repositories"
 	"github.com/Spoloborota/experiment/internal/domain/services"
 	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
@@ -20,28 +25,54 @@ type ProfileHandler struct {
 }
 
 type CreateProfileRequest struct {
-	FirstName string   `json:"first_name"`
-	LastName  string   `json:"last_name"`
-	Age       int      `json:"age"`
-	Gender    string   `json:"gender"`
-	City      string   `json:"city"`
-	Interests []string `json:"interests"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	BirthDate entities.Date `json:"birth_date" swaggertype:"string" example:"1990-05-17"`
+	Gender    string        `json:"gender"`
+	City      string        `json:"city"`
+	Interests []string      `json:"interests"`
 }
 
 type UpdateProfileRequest struct {
-	FirstName string   `json:"first_name"`
-	LastName  string   `json:"last_name"`
-	Age       int      `json:"age"`
-	Gender    string   `json:"gender"`
-	City      string   `json:"city"`
-	Interests []string `json:"interests"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	BirthDate entities.Date `json:"birth_date" swaggertype:"string" example:"1990-05-17"`
+	Gender    string        `json:"gender"`
+	City      string        `json:"city"`
+	Interests []string      `json:"interests"`
+}
+
+// PatchProfileRequest описывает тело PATCH запроса в формате JSON Merge Patch (RFC 7396):
+// отсутствующие поля не меняются, null очищает необязательные поля city и interests
+type PatchProfileRequest struct {
+	FirstName *string        `json:"first_name,omitempty"`
+	LastName  *string        `json:"last_name,omitempty"`
+	BirthDate *entities.Date `json:"birth_date,omitempty" swaggertype:"string" example:"1990-05-17"`
+	Gender    *string        `json:"gender,omitempty"`
+	City      *string        `json:"city,omitempty"`
+	Interests *[]string      `json:"interests,omitempty"`
+}
+
+// UpdatePrivacyRequest описывает новые настройки видимости: public, registered, friends или private.
+// Отсутствующие поля не меняются
+type UpdatePrivacyRequest struct {
+	Profile   *entities.Visibility `json:"profile,omitempty"`
+	Age       *entities.Visibility `json:"age,omitempty"`
+	Birthday  *entities.Visibility `json:"birthday,omitempty"`
+	Zodiac    *entities.Visibility `json:"zodiac_sign,omitempty"`
+	Gender    *entities.Visibility `json:"gender,omitempty"`
+	City      *entities.Visibility `json:"city,omitempty"`
+	Interests *entities.Visibility `json:"interests,omitempty"`
+	Photos    *entities.Visibility `json:"photos,omitempty"`
 }
 
 type ProfilesResponse struct {
-	Profiles []interface{} `json:"profiles"`
-	Total    int           `json:"total"`
-	Limit    int           `json:"limit"`
-	Offset   int           `json:"offset"`
+	Profiles   []interface{} `json:"profiles"`
+	Total      int           `json:"total"`
+	Limit      int           `json:"limit"`
+	Offset     int           `json:"offset"`
+	Sort       string        `json:"sort"`
+	NextCursor string        `json:"next_cursor,omitempty"` // Курсор следующей страницы, отсутствует на последней
 }
 
 func NewProfileHandler(profileService *services.ProfileService, logger *zap.Logger) *ProfileHandler {
@@ -84,7 +115,7 @@ func (h *ProfileHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 		user.UserID,
 		req.FirstName,
 		req.LastName,
-		req.Age,
+		req.BirthDate,
 		req.Gender,
 		req.City,
 		req.Interests,
@@ -96,19 +127,25 @@ func (h *ProfileHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", profileETag(profile))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(profile)
 }
 
 // GetProfile godoc
 // @Summary Получение профиля по ID
-// @Description Возвращает профиль пользователя по его ID
+// @Description Возвращает профиль пользователя по его ID. Доступен без авторизации; поля, скрытые владельцем от зрителя,
+// @Description не возвращаются, а скрытая от зрителя анкета не находится. Ответ содержит ETag; при совпадении If-None-Match возвращается 304
 // @Tags profiles
 // @Produce json
 // @Param id path int true "ID профиля"
+// @Param If-None-Match header string false "ETag сохраненной версии профиля"
 // @Success 200 {object} map[string]interface{}
+// @Success 304 "Профиль не изменился"
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/v1/profile/{id} [get]
 func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -118,23 +155,36 @@ func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profile, err := h.profileService.GetProfile(r.Context(), id)
+	profile, relation, err := h.profileService.GetProfile(r.Context(), id, viewerID(r))
 	if err != nil {
 		h.logger.Error("Failed to get profile", zap.Error(err))
 		h.writeErrorResponse(w, "Profile not found", http.StatusNotFound)
 		return
 	}
 
+	// Ответ зависит от того, кто смотрит анкету
+	w.Header().Set("Vary", "Authorization, "+middleware.APIKeyHeader)
+	if relation == entities.ViewerAnonymous {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	if writeNotModified(w, r, profileViewETag(profile, relation)) {
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(profile)
 }
 
 // GetMyProfile godoc
 // @Summary Получение собственного профиля
-// @Description Возвращает профиль текущего авторизованного пользователя
+// @Description Возвращает профиль текущего авторизованного пользователя. Ответ содержит ETag; при совпадении If-None-Match возвращается 304
 // @Tags profiles
 // @Produce json
+// @Param If-None-Match header string false "ETag сохраненной версии профиля"
 // @Success 200 {object} map[string]interface{}
+// @Success 304 "Профиль не изменился"
 // @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Security BearerAuth
@@ -154,20 +204,27 @@ func (h *ProfileHandler) GetMyProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if writeNotModified(w, r, profileETag(profile)) {
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(profile)
 }
 
 // UpdateProfile godoc
 // @Summary Обновление профиля
-// @Description Обновляет профиль текущего пользователя
+// @Description Обновляет профиль текущего пользователя. С заголовком If-Match профиль обновляется, только если его ETag не изменился
 // @Tags profiles
 // @Accept json
 // @Produce json
+// @Param If-Match header string false "ETag версии профиля, которую редактирует клиент"
 // @Param request body UpdateProfileRequest true "Данные для обновления профиля"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/profile/me [put]
 func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
@@ -178,6 +235,12 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedVersion, ok := ifMatchVersion(r)
+	if !ok {
+		h.writeErrorResponse(w, repositories.ErrProfileVersionMismatch.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
 	var req UpdateProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.logger.Error("Failed to decode update profile request", zap.Error(err))
@@ -189,57 +252,179 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	profile, err := h.profileService.UpdateProfile(
 		r.Context(),
 		user.UserID,
+		expectedVersion,
 		req.FirstName,
 		req.LastName,
-		req.Age,
+		req.BirthDate,
 		req.Gender,
 		req.City,
 		req.Interests,
 	)
 	if err != nil {
+		if errors.Is(err, repositories.ErrProfileVersionMismatch) {
+			h.writeErrorResponse(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
 		h.logger.Error("Failed to update profile", zap.Error(err))
 		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", profileETag(profile))
+	json.NewEncoder(w).Encode(profile)
+}
+
+// PatchProfile godoc
+// @Summary Частичное обновление профиля
+// @Description Обновляет только переданные поля профиля текущего пользователя (JSON Merge Patch, RFC 7396).
+// @Description Отсутствующие поля не меняются, null очищает город и интересы. Имя, фамилию, дату рождения и пол очистить нельзя
+// @Tags profiles
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param If-Match header string false "ETag версии профиля, которую редактирует клиент"
+// @Param request body PatchProfileRequest true "Изменяемые поля профиля"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me [patch]
+func (h *ProfileHandler) PatchProfile(w http.ResponseWriter, r *http.Request) {
+	// Получаем информацию о пользователе из контекста
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	expectedVersion, ok := ifMatchVersion(r)
+	if !ok {
+		h.writeErrorResponse(w, repositories.ErrProfileVersionMismatch.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	patch, err := decodeProfilePatch(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profileService.PatchProfile(r.Context(), user.UserID, expectedVersion, patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrProfileNotFound):
+			h.writeErrorResponse(w, "Profile not found", http.StatusNotFound)
+		case errors.Is(err, repositories.ErrProfileVersionMismatch):
+			h.writeErrorResponse(w, err.Error(), http.StatusPreconditionFailed)
+		default:
+			h.logger.Error("Failed to patch profile", zap.Error(err))
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", profileETag(profile))
 	json.NewEncoder(w).Encode(profile)
 }
 
 // SearchProfiles godoc
 // @Summary Поиск профилей
-// @Description Ищет профили по заданным фильтрам
+// @Description Ищет профили по заданным фильтрам. Анкеты, скрытые от зрителя, не находятся, фильтры по скрытым от него полям
+// @Description не совпадают, а скрытые поля не возвращаются. Авторизация необязательна, если не требуется подтвержденный email.
+// @Description С параметром q анкеты содержат match: релевантность и совпадения, выделенные тегом <mark>.
+// @Description Страницы читаются по смещению (offset) или по курсору: next_cursor из ответа передается в cursor
+// @Description вместе с теми же фильтрами, и следующая страница не сдвигается при добавлении и удалении анкет
 // @Tags profiles
 // @Produce json
-// @Param gender query string false "Фильтр по полу"
-// @Param city query string false "Фильтр по городу"
-// @Param interests query string false "Фильтр по интересам (через запятую)"
-// @Param limit query int false "Лимит результатов" default(10)
+// @Param gender query []string false "Фильтр по полу: любое из значений (через запятую или повтором параметра)" collectionFormat(multi)
+// @Param city query []string false "Фильтр по городу: любое из значений" collectionFormat(multi)
+// @Param interests query []string false "Фильтр по интересам" collectionFormat(multi)
+// @Param interests_match query string false "Совпадение с любым (any) или со всеми (all) интересами" Enums(any, all) default(any)
+// @Param gender! query []string false "Исключить анкеты с полом" collectionFormat(multi)
+// @Param city! query []string false "Исключить анкеты из городов" collectionFormat(multi)
+// @Param interests! query []string false "Исключить анкеты с любым из интересов" collectionFormat(multi)
+// @Param age_min query int false "Возраст не меньше"
+// @Param age_max query int false "Возраст не больше"
+// @Param birth_date_from query string false "Дата рождения не раньше (YYYY-MM-DD)"
+// @Param birth_date_to query string false "Дата рождения не позже (YYYY-MM-DD)"
+// @Param q query string false "Текстовый запрос по имени, городу и интересам с учетом опечаток; результаты упорядочены по релевантности"
+// @Param first_name query string false "Начало имени без учета регистра"
+// @Param last_name query string false "Начало фамилии без учета регистра"
+// @Param sort query string false "Порядок: newest - новые, name - по имени, age - по возрасту, relevance - по релевантности q. По умолчанию relevance с q, иначе newest" Enums(newest, name, age, relevance)
+// @Param limit query int false "Лимит результатов, не больше 100" default(10)
 // @Param offset query int false "Смещение" default(0)
+// @Param cursor query string false "Курсор next_cursor из предыдущего ответа, заменяет offset"
 // @Success 200 {object} ProfilesResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/v1/profiles [get]
 func (h *ProfileHandler) SearchProfiles(w http.ResponseWriter, r *http.Request) {
 	// Парсим параметры запроса
 	filters := repositories.SearchFilters{
-		Limit:  10, // По умолчанию
-		Offset: 0,
+		Limit:    repositories.DefaultSearchLimit,
+		Offset:   0,
+		ViewerID: viewerID(r),
 	}
 
-	if gender := r.URL.Query().Get("gender"); gender != "" {
-		filters.Gender = &gender
+	// Несколько значений передаются через запятую или повтором параметра, исключения - параметрами с "!"
+	filters.Genders = listQueryParam(r, "gender")
+	filters.Cities = listQueryParam(r, "city")
+	filters.Interests = listQueryParam(r, "interests")
+	filters.ExcludeGenders = listQueryParam(r, "gender!")
+	filters.ExcludeCities = listQueryParam(r, "city!")
+	filters.ExcludeInterests = listQueryParam(r, "interests!")
+
+	filters.InterestsMatch = repositories.InterestsMatchAny
+	if match := r.URL.Query().Get("interests_match"); match != "" {
+		filters.InterestsMatch = repositories.InterestsMatch(match)
+		if !filters.InterestsMatch.IsValid() {
+			h.writeErrorResponse(w, "interests_match must be any or all", http.StatusBadRequest)
+			return
+		}
 	}
 
-	if city := r.URL.Query().Get("city"); city != "" {
-		filters.City = &city
+	if query := strings.TrimSpace(r.URL.Query().Get("q")); query != "" {
+		if utf8.RuneCountInString(query) > entities.MaxSearchQueryLength {
+			h.writeErrorResponse(w, fmt.Sprintf("q must be at most %d characters", entities.MaxSearchQueryLength), http.StatusBadRequest)
+			return
+		}
+		filters.Query = &query
 	}
 
-	if interestsStr := r.URL.Query().Get("interests"); interestsStr != "" {
-		interests := strings.Split(interestsStr, ",")
-		for i, interest := range interests {
-			interests[i] = strings.TrimSpace(interest)
-		}
-		filters.Interests = interests
+	if firstName := strings.TrimSpace(r.URL.Query().Get("first_name")); firstName != "" {
+		filters.FirstName = &firstName
+	}
+
+	if lastName := strings.TrimSpace(r.URL.Query().Get("last_name")); lastName != "" {
+		filters.LastName = &lastName
+	}
+
+	var err error
+	if filters.AgeMin, err = ageQueryParam(r, "age_min"); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filters.AgeMax, err = ageQueryParam(r, "age_max"); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filters.AgeMin != nil && filters.AgeMax != nil && *filters.AgeMin > *filters.AgeMax {
+		h.writeErrorResponse(w, "age_min must not be greater than age_max", http.StatusBadRequest)
+		return
+	}
+
+	if filters.BirthDateFrom, err = dateQueryParam(r, "birth_date_from"); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filters.BirthDateTo, err = dateQueryParam(r, "birth_date_to"); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -254,33 +439,216 @@ func (h *ProfileHandler) SearchProfiles(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		filters.Sort = repositories.SearchSort(sort)
+		if !filters.Sort.IsValid() {
+			h.writeErrorResponse(w, "sort must be one of newest, name, age, relevance", http.StatusBadRequest)
+			return
+		}
+		if filters.Sort == repositories.SearchSortRelevance && filters.Query == nil {
+			h.writeErrorResponse(w, "sort=relevance requires q", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		if filters.Offset > 0 {
+			h.writeErrorResponse(w, "cursor cannot be combined with offset", http.StatusBadRequest)
+			return
+		}
+		if filters.Cursor, err = repositories.DecodeSearchCursor(token); err != nil {
+			h.writeErrorResponse(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Ищем профили
-	profiles, total, err := h.profileService.SearchProfiles(r.Context(), filters)
+	result, err := h.profileService.SearchProfiles(r.Context(), filters)
 	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidSearchCursor) {
+			h.writeErrorResponse(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
 		h.logger.Error("Failed to search profiles", zap.Error(err))
 		h.writeErrorResponse(w, "Failed to search profiles", http.StatusInternalServerError)
 		return
 	}
 
 	// Конвертируем в interface{} для JSON
-	profilesInterface := make([]interface{}, len(profiles))
-	for i, profile := range profiles {
+	profilesInterface := make([]interface{}, len(result.Profiles))
+	for i, profile := range result.Profiles {
 		profilesInterface[i] = profile
 	}
 
 	response := ProfilesResponse{
 		Profiles: profilesInterface,
-		Total:    total,
-		Limit:    filters.Limit,
+		Total:    result.Total,
+		Limit:    result.Limit,
 		Offset:   filters.Offset,
+		Sort:     string(result.Sort),
+	}
+	if result.NextCursor != nil {
+		response.NextCursor = result.NextCursor.Encode()
 	}
 
+	w.Header().Set("Vary", "Authorization, "+middleware.APIKeyHeader)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// UpdatePrivacy godoc
+// @Summary Настройки видимости анкеты
+// @Description Меняет, кому видна анкета и ее поля: public - всем, registered - авторизованным пользователям,
+// @Description friends - друзьям, private - только владельцу. Отсутствующие поля не меняются
+// @Tags profiles
+// @Accept json
+// @Produce json
+// @Param request body UpdatePrivacyRequest true "Новые настройки видимости"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/profile/me/privacy [patch]
+func (h *ProfileHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdatePrivacyRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profileService.UpdatePrivacy(r.Context(), user.UserID, entities.ProfilePrivacyPatch(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrProfileNotFound):
+			h.writeErrorResponse(w, "Profile not found", http.StatusNotFound)
+		case errors.Is(err, entities.ErrInvalidVisibility):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Error("Failed to update profile privacy", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to update profile privacy", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", profileETag(profile))
+	json.NewEncoder(w).Encode(profile)
+}
+
 func (h *ProfileHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
 }
+
+// viewerID возвращает ID пользователя, который смотрит анкеты, или 0 для неавторизованного запроса.
+// API ключ без доступа к анкетам не дает видеть больше, чем видно анонимам
+func viewerID(r *http.Request) int {
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok && user.HasScope(entities.ScopeProfilesRead) {
+		return user.UserID
+	}
+	return 0
+}
+
+// listQueryParam возвращает значения параметра, переданные через запятую или повтором параметра
+func listQueryParam(r *http.Request, name string) []string {
+	var values []string
+	for _, param := range r.URL.Query()[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// ageQueryParam разбирает необязательный параметр с возрастом в годах
+func ageQueryParam(r *http.Request, name string) (*int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	age, err := strconv.Atoi(value)
+	if err != nil || age < 0 || age > entities.MaxAge {
+		return nil, fmt.Errorf("%s must be an integer from 0 to %d", name, entities.MaxAge)
+	}
+
+	return &age, nil
+}
+
+// dateQueryParam возвращает дату из параметра запроса или nil, если параметр не задан
+func dateQueryParam(r *http.Request, name string) (*entities.Date, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := entities.ParseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return &date, nil
+}
+
+// decodeProfilePatch разбирает тело PATCH запроса. В отличие от обычного декодирования JSON
+// различает отсутствующее поле и явный null
+func decodeProfilePatch(r *http.Request) (entities.ProfilePatch, error) {
+	var patch entities.ProfilePatch
+
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil || fields == nil {
+		return patch, errors.New("Invalid request body")
+	}
+
+	for name, raw := range fields {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+		var target interface{}
+		switch name {
+		case "first_name":
+			target = &patch.FirstName
+		case "last_name":
+			target = &patch.LastName
+		case "birth_date":
+			target = &patch.BirthDate
+		case "gender":
+			target = &patch.Gender
+		case "city":
+			if isNull {
+				patch.City = new(string)
+				continue
+			}
+			target = &patch.City
+		case "interests":
+			if isNull {
+				patch.Interests = &[]string{}
+				continue
+			}
+			target = &patch.Interests
+		default:
+			return patch, fmt.Errorf("unknown field %q", name)
+		}
+
+		if isNull {
+			return patch, fmt.Errorf("%s cannot be null", name)
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return patch, fmt.Errorf("invalid value for %s", name)
+		}
+	}
+
+	return patch, nil
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+)
+
+func TestDecodeProfilePatch(t *testing.T) {
+	ptr := func(value string) *string { return &value }
+	birthDate := entities.NewDate(1990, time.May, 17)
+
+	tests := []struct {
+		name      string
+		body      string
+		want      entities.ProfilePatch
+		wantError string // Пусто, если тело должно разобраться без ошибки
+	}{
+		{
+			name: "empty object",
+			body: `{}`,
+			want: entities.ProfilePatch{},
+		},
+		{
+			name: "all fields",
+			body: `{"first_name":"Анна","last_name":"Иванова","birth_date":"1990-05-17","gender":"female","city":"Москва","interests":["music","travel"]}`,
+			want: entities.ProfilePatch{
+				FirstName: ptr("Анна"),
+				LastName:  ptr("Иванова"),
+				BirthDate: &birthDate,
+				Gender:    ptr("female"),
+				City:      ptr("Москва"),
+				Interests: &[]string{"music", "travel"},
+			},
+		},
+		{
+			name: "missing fields are not changed",
+			body: `{"city":"Москва"}`,
+			want: entities.ProfilePatch{City: ptr("Москва")},
+		},
+		{
+			name: "null city clears it",
+			body: `{"city":null}`,
+			want: entities.ProfilePatch{City: ptr("")},
+		},
+		{
+			name: "null interests clear them",
+			body: `{"interests": null}`,
+			want: entities.ProfilePatch{Interests: &[]string{}},
+		},
+		{
+			name: "empty city and interests",
+			body: `{"city":"","interests":[]}`,
+			want: entities.ProfilePatch{City: ptr(""), Interests: &[]string{}},
+		},
+		{
+			name:      "null first name",
+			body:      `{"first_name":null}`,
+			wantError: "first_name cannot be null",
+		},
+		{
+			name:      "null last name",
+			body:      `{"last_name":null}`,
+			wantError: "last_name cannot be null",
+		},
+		{
+			name:      "null birth date",
+			body:      `{"birth_date":null}`,
+			wantError: "birth_date cannot be null",
+		},
+		{
+			name:      "null gender",
+			body:      `{"gender":null}`,
+			wantError: "gender cannot be null",
+		},
+		{
+			name:      "unknown field",
+			body:      `{"first_name":"Анна","age":30}`,
+			wantError: `unknown field "age"`,
+		},
+		{
+			name:      "field names are case sensitive",
+			body:      `{"First_Name":"Анна"}`,
+			wantError: `unknown field "First_Name"`,
+		},
+		{
+			name:      "invalid birth date",
+			body:      `{"birth_date":"17.05.1990"}`,
+			wantError: "invalid value for birth_date",
+		},
+		{
+			name:      "wrong type",
+			body:      `{"interests":"music"}`,
+			wantError: "invalid value for interests",
+		},
+		{
+			name:      "null body",
+			body:      `null`,
+			wantError: "Invalid request body",
+		},
+		{
+			name:      "not an object",
+			body:      `["first_name"]`,
+			wantError: "Invalid request body",
+		},
+		{
+			name:      "malformed json",
+			body:      `{"first_name":`,
+			wantError: "Invalid request body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/api/v1/profile", strings.NewReader(tt.body))
+			patch, err := decodeProfilePatch(r)
+
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Fatalf("decodeProfilePatch() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeProfilePatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(patch, tt.want) {
+				t.Errorf("decodeProfilePatch() = %+v, want %+v", patch, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type SessionHandler struct {
+	authService *services.AuthService
+	logger      *zap.Logger
+}
+
+// SessionResponse описывает сессию в списке; Current отмечает сессию, из которой выполнен запрос
+type SessionResponse struct {
+	*entities.Session
+	Current bool `json:"current"`
+}
+
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+func NewSessionHandler(authService *services.AuthService, logger *zap.Logger) *SessionHandler {
+	return &SessionHandler{
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// ListSessions godoc
+// @Summary Список сессий
+// @Description Возвращает устройства, на которых выполнен вход: User-Agent, IP, время входа и последней активности. Последняя активность обновляется при обновлении токенов
+// @Tags account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/sessions [get]
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(r.Context(), user.UserID)
+	if err != nil {
+		h.logger.Error("Failed to list sessions", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to list sessions", http.StatusInternalServerError)
+		return
+	}
+
+	response := SessionsResponse{Sessions: make([]SessionResponse, len(sessions))}
+	for i, session := range sessions {
+		response.Sessions[i] = SessionResponse{
+			Session: session,
+			Current: session.ID == user.SessionID,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeSession godoc
+// @Summary Завершение сессии
+// @Description Завершает сессию на выбранном устройстве: access и refresh токены этой сессии перестают действовать
+// @Tags account
+// @Param id path string true "ID сессии"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID := chi.URLParam(r, "id")
+	if err := h.authService.RevokeSession(r.Context(), user.UserID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to revoke session", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Session revoked", zap.Int("user_id", user.UserID))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SessionHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+// writeThrottledResponse отвечает 429 с заголовком Retry-After и пишет в лог события блокировки
+func writeThrottledResponse(w http.ResponseWriter, r *http.Request, logger *zap.Logger, email string, throttled *services.LoginThrottledError) {
+	retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+
+	fields := []zap.Field{
+		zap.String("request_id", chimiddleware.GetReqID(r.Context())),
+		zap.String("ip", middleware.ClientIP(r)),
+		zap.String("scope", throttled.Scope),
+		zap.Int("retry_after_seconds", retryAfter),
+	}
+	if email != "" {
+		fields = append(fields, zap.String("email", email))
+	}
+
+	switch {
+	case throttled.Lockout && throttled.Triggered:
+		logger.Warn("Login lockout triggered", fields...)
+	case throttled.Lockout:
+		logger.Info("Login attempt rejected during lockout", fields...)
+	default:
+		logger.Debug("Login attempt delayed", fields...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: throttled.Error()})
+}
+
+// clientInfo возвращает сведения о клиенте для сессии, которая открывается запросом
+func clientInfo(r *http.Request) services.ClientInfo {
+	return services.ClientInfo{
+		IP:        middleware.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
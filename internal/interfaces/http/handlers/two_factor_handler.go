@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+	"github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+	accountService   *services.AccountService
+	logger           *zap.Logger
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService, accountService *services.AccountService, logger *zap.Logger) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		accountService:   accountService,
+		logger:           logger,
+	}
+}
+
+// Enroll godoc
+// @Summary Начало подключения 2FA
+// @Description Генерирует секрет TOTP и otpauth URI для приложения-аутентификатора. 2FA включается после подтверждения кодом
+// @Tags 2fa
+// @Produce json
+// @Success 200 {object} TwoFactorEnrollResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(r.Context(), user.UserID)
+	if err != nil {
+		if errors.Is(err, services.ErrTwoFactorAlreadyEnabled) {
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to enroll two-factor authentication", zap.Error(err))
+		h.writeErrorResponse(w, "Failed to enroll two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TwoFactorEnrollResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	})
+}
+
+// Confirm godoc
+// @Summary Подтверждение подключения 2FA
+// @Description Включает 2FA после проверки первого кода и возвращает одноразовые коды восстановления. Коды показываются только один раз
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorCodeRequest true "Код из приложения-аутентификатора"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(r.Context(), user.UserID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTwoFactorCode), errors.Is(err, services.ErrTwoFactorNotEnrolled):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("Failed to confirm two-factor authentication", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to confirm two-factor authentication", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary Отключение 2FA
+// @Description Отключает 2FA. Требуются текущий пароль и действующий код из приложения-аутентификатора или код восстановления
+// @Tags 2fa
+// @Accept json
+// @Param request body DisableTwoFactorRequest true "Текущий пароль и код из приложения-аутентификатора или код восстановления"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/account/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, "User not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req DisableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" || req.Code == "" {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.accountService.DisableTwoFactor(r.Context(), user.UserID, req.Password, req.Code, middleware.ClientIP(r)); err != nil {
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			writeThrottledResponse(w, r, h.logger, user.Email, throttled)
+		case errors.Is(err, services.ErrIncorrectPassword):
+			h.writeErrorResponse(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, services.ErrInvalidTwoFactorCode), errors.Is(err, services.ErrTwoFactorNotEnabled):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Error("Failed to disable two-factor authentication", zap.Error(err))
+			h.writeErrorResponse(w, "Failed to disable two-factor authentication", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TwoFactorHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+// APIKeyHeader заголовок, в котором машинные клиенты передают API ключ
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyOrJWTAuthMiddleware создает middleware, который принимает API ключ из заголовка X-API-Key,
+// а при его отсутствии проверяет JWT токен так же, как JWTAuthMiddleware.
+// В обоих случаях в контекст попадают claims, которые возвращает GetUserFromContext.
+func APIKeyOrJWTAuthMiddleware(authService *services.AuthService, apiKeyService *services.APIKeyService) func(http.Handler) http.Handler {
+	jwtAuth := JWTAuthMiddleware(authService)
+
+	return func(next http.Handler) http.Handler {
+		jwtNext := jwtAuth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get(APIKeyHeader)
+			if apiKey == "" {
+				jwtNext.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := apiKeyService.Authenticate(r.Context(), apiKey)
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidAPIKey) {
+					http.Error(w, "Invalid API key", http.StatusUnauthorized)
+					return
+				}
+				http.Error(w, "Failed to verify API key", http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalAuthMiddleware создает middleware для публичных роутов: запрос без JWT токена и API ключа
+// проходит как анонимный, а переданные учетные данные проверяются так же, как в APIKeyOrJWTAuthMiddleware.
+// Неверный токен не превращает запрос в анонимный, а отклоняется, чтобы клиент узнал об ошибке
+func OptionalAuthMiddleware(authService *services.AuthService, apiKeyService *services.APIKeyService) func(http.Handler) http.Handler {
+	auth := APIKeyOrJWTAuthMiddleware(authService, apiKeyService)
+
+	return func(next http.Handler) http.Handler {
+		authNext := auth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" && r.Header.Get(APIKeyHeader) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authNext.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope создает middleware, который пропускает API ключи только с указанной областью доступа.
+// Запросы с JWT токеном проходят всегда. Должен использоваться после APIKeyOrJWTAuthMiddleware.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Authorization is required", http.StatusUnauthorized)
+				return
+			}
+
+			if !user.HasScope(scope) {
+				http.Error(w, "API key does not have the required scope", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -39,6 +39,17 @@ func JWTAuthMiddleware(authService *services.AuthService) func(http.Handler) htt
 				return
 			}
 
+			// Проверяем, не был ли токен отозван
+			revoked, err := authService.IsTokenRevoked(r.Context(), claims)
+			if err != nil {
+				http.Error(w, "Failed to verify token", http.StatusInternalServerError)
+				return
+			}
+			if revoked {
+				http.Error(w, "Token has been revoked", http.StatusUnauthorized)
+				return
+			}
+
 			// Добавляем информацию о пользователе в контекст
 			ctx := context.WithValue(r.Context(), UserContextKey, claims)
 			r = r.WithContext(ctx)
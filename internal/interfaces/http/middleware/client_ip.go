@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+)
+
+// ClientIP возвращает адрес клиента. Заголовки прокси уже учтены middleware.RealIP
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
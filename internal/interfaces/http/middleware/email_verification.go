@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+// RequireVerifiedEmail создает middleware, который пропускает только пользователей с подтвержденным email.
+// Должен использоваться после JWTAuthMiddleware.
+func RequireVerifiedEmail(verificationService *services.EmailVerificationService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Authorization is required", http.StatusUnauthorized)
+				return
+			}
+
+			verified, err := verificationService.IsVerified(r.Context(), user.UserID)
+			if err != nil {
+				http.Error(w, "Failed to check email verification", http.StatusInternalServerError)
+				return
+			}
+			if !verified {
+				http.Error(w, "Email address is not verified", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/Spoloborota/experiment/internal/domain/entities"
+	"github.com/Spoloborota/experiment/internal/domain/services"
+)
+
+// ImpersonatedByHeader заголовок ответа с ID администратора, который действует от имени пользователя
+const ImpersonatedByHeader = "X-Impersonated-By"
+
+// AuditImpersonation создает middleware, который помечает ответы на запросы с токеном входа
+// под пользователем заголовком X-Impersonated-By и записывает каждый такой запрос в журнал аудита.
+// Запросы с обычным токеном или API ключом пропускаются без изменений.
+// Должен использоваться после JWTAuthMiddleware или APIKeyOrJWTAuthMiddleware.
+func AuditImpersonation(auditService *services.AuditService, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok || !user.IsImpersonated() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(ImpersonatedByHeader, strconv.Itoa(user.Actor.UserID))
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// Запрос уже обработан, поэтому запись не должна прерываться при отключении клиента
+			ctx := context.WithoutCancel(r.Context())
+			err := auditService.Record(ctx, &entities.AuditLogEntry{
+				ActorID:   user.Actor.UserID,
+				UserID:    user.UserID,
+				Action:    entities.AuditActionImpersonatedRequest,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    status,
+				IP:        ClientIP(r),
+				RequestID: chimiddleware.GetReqID(r.Context()),
+			})
+			if err != nil {
+				logger.Error("Failed to record impersonated request",
+					zap.Error(err),
+					zap.Int("admin_id", user.Actor.UserID),
+					zap.Int("user_id", user.UserID),
+					zap.String("path", r.URL.Path),
+				)
+			}
+		})
+	}
+}
+
+// DenyImpersonation запрещает действие, если администратор вошел под пользователем.
+// Используется для смены пароля, удаления аккаунта и других чувствительных действий.
+// Должен использоваться после JWTAuthMiddleware.
+func DenyImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUserFromContext(r.Context())
+		if !ok {
+			http.Error(w, "Authorization is required", http.StatusUnauthorized)
+			return
+		}
+
+		if user.IsImpersonated() {
+			http.Error(w, "This action is not allowed while impersonating a user", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// RequireRole создает middleware, который пропускает только пользователей с одной из ролей.
+// Роли берутся из access токена, поэтому изменения ролей вступают в силу после обновления токена.
+// Должен использоваться после JWTAuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Authorization is required", http.StatusUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if user.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		})
+	}
+}
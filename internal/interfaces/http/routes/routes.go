@@ -9,22 +9,50 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
 
+	"github.com/Spoloborota/experiment/internal/domain/entities"
 	"github.com/Spoloborota/experiment/internal/domain/services"
 	"github.com/Spoloborota/experiment/internal/interfaces/http/handlers"
 	authMiddleware "github.com/Spoloborota/experiment/internal/interfaces/http/middleware"
 )
 
+// Services содержит сервисы, которые используют обработчики запросов
+type Services struct {
+	Auth              *services.AuthService
+	Profile           *services.ProfileService
+	Account           *services.AccountService
+	Admin             *services.AdminService
+	Audit             *services.AuditService
+	PasswordReset     *services.PasswordResetService
+	EmailVerification *services.EmailVerificationService
+	TwoFactor         *services.TwoFactorService
+	APIKeys           *services.APIKeyService
+	OAuth             *services.OAuthService
+	Photos            *services.PhotoService
+	Interests         *services.InterestService
+	Keys              services.KeyProvider
+}
+
+// Options содержит политики доступа к роутам
+type Options struct {
+	// RequireVerifiedEmail закрывает создание анкеты и поиск для пользователей без подтвержденного email
+	RequireVerifiedEmail bool
+	// MediaPath - путь, по которому раздаются загруженные файлы, если их отдает сам сервер
+	MediaPath string
+	// MediaHandler раздает загруженные файлы; nil, если файлы отдает внешнее хранилище
+	MediaHandler http.Handler
+}
+
 type Routes struct {
-	authService    *services.AuthService
-	profileService *services.ProfileService
-	logger         *zap.Logger
+	services Services
+	options  Options
+	logger   *zap.Logger
 }
 
-func NewRoutes(authService *services.AuthService, profileService *services.ProfileService, logger *zap.Logger) *Routes {
+func NewRoutes(svc Services, options Options, logger *zap.Logger) *Routes {
 	return &Routes{
-		authService:    authService,
-		profileService: profileService,
-		logger:         logger,
+		services: svc,
+		options:  options,
+		logger:   logger,
 	}
 }
 
@@ -40,9 +68,9 @@ func (rt *Routes) Setup() *chi.Mux {
 	// CORS
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match", authMiddleware.APIKeyHeader},
+		ExposedHeaders:   []string{"Link", "ETag", authMiddleware.ImpersonatedByHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -51,8 +79,28 @@ func (rt *Routes) Setup() *chi.Mux {
 	r.Get("/swagger/*", httpSwagger.Handler())
 
 	// Создаем обработчики
-	authHandler := handlers.NewAuthHandler(rt.authService, rt.logger)
-	profileHandler := handlers.NewProfileHandler(rt.profileService, rt.logger)
+	authHandler := handlers.NewAuthHandler(rt.services.Auth, rt.services.EmailVerification, rt.logger)
+	profileHandler := handlers.NewProfileHandler(rt.services.Profile, rt.logger)
+	accountHandler := handlers.NewAccountHandler(rt.services.Account, rt.services.EmailVerification, rt.logger)
+	passwordHandler := handlers.NewPasswordHandler(rt.services.PasswordReset, rt.logger)
+	emailVerificationHandler := handlers.NewEmailVerificationHandler(rt.services.EmailVerification, rt.logger)
+	adminHandler := handlers.NewAdminHandler(rt.services.Admin, rt.logger)
+	twoFactorHandler := handlers.NewTwoFactorHandler(rt.services.TwoFactor, rt.services.Account, rt.logger)
+	apiKeyHandler := handlers.NewAPIKeyHandler(rt.services.APIKeys, rt.logger)
+	sessionHandler := handlers.NewSessionHandler(rt.services.Auth, rt.logger)
+	oauthHandler := handlers.NewOAuthHandler(rt.services.OAuth, rt.logger)
+	photoHandler := handlers.NewPhotoHandler(rt.services.Photos, rt.logger)
+	interestHandler := handlers.NewInterestHandler(rt.services.Interests, rt.logger)
+	keysHandler := handlers.NewKeysHandler(rt.services.Keys)
+
+	// Middleware авторизации и проверки подтвержденного email
+	requireAuth := authMiddleware.JWTAuthMiddleware(rt.services.Auth)
+	requireAuthOrAPIKey := authMiddleware.APIKeyOrJWTAuthMiddleware(rt.services.Auth, rt.services.APIKeys)
+	optionalAuth := authMiddleware.OptionalAuthMiddleware(rt.services.Auth, rt.services.APIKeys)
+	requireVerifiedEmail := authMiddleware.RequireVerifiedEmail(rt.services.EmailVerification)
+
+	// Запросы администратора от имени пользователя помечаются и записываются в журнал аудита
+	auditImpersonation := authMiddleware.AuditImpersonation(rt.services.Audit, rt.logger)
 
 	// Health check endpoint
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -61,6 +109,14 @@ func (rt *Routes) Setup() *chi.Mux {
 		w.Write([]byte(`{"status":"ok","service":"social-network"}`))
 	})
 
+	// Публичные ключи для проверки токенов другими сервисами
+	r.Get("/.well-known/jwks.json", keysHandler.JWKS)
+
+	// Загруженные фотографии, если они хранятся на диске сервера
+	if rt.options.MediaHandler != nil {
+		r.Handle(rt.options.MediaPath+"/*", http.StripPrefix(rt.options.MediaPath, rt.options.MediaHandler))
+	}
+
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Health check for API
@@ -73,16 +129,115 @@ func (rt *Routes) Setup() *chi.Mux {
 		// Публичные роуты (без авторизации)
 		r.Post("/register", authHandler.Register)
 		r.Post("/login", authHandler.Login)
-		r.Get("/profile/{id}", profileHandler.GetProfile)
-		r.Get("/profiles", profileHandler.SearchProfiles)
+		r.Post("/login/2fa", authHandler.LoginTwoFactor)
+		r.Post("/token/refresh", authHandler.RefreshToken)
+		r.Get("/oauth/{provider}/authorize", oauthHandler.Authorize)
+		r.Get("/oauth/{provider}/callback", oauthHandler.Callback)
+		r.Post("/password/forgot", passwordHandler.ForgotPassword)
+		r.Post("/password/reset", passwordHandler.ResetPassword)
+		r.Post("/email/verify", emailVerificationHandler.VerifyEmail)
+		r.Get("/interests", interestHandler.SuggestInterests)
+
+		// Анкеты доступны без авторизации; авторизованный зритель видит поля, скрытые от анонимов
+		r.With(optionalAuth, auditImpersonation).Get("/profile/{id}", profileHandler.GetProfile)
+
+		// Поиск доступен без авторизации, если не требуется подтвержденный email
+		if rt.options.RequireVerifiedEmail {
+			r.With(
+				requireAuthOrAPIKey,
+				auditImpersonation,
+				authMiddleware.RequireScope(entities.ScopeProfilesRead),
+				requireVerifiedEmail,
+			).Get("/profiles", profileHandler.SearchProfiles)
+		} else {
+			r.With(optionalAuth, auditImpersonation).Get("/profiles", profileHandler.SearchProfiles)
+		}
+
+		// Роуты анкеты доступны и по JWT токену, и по API ключу с нужной областью доступа
+		r.Group(func(r chi.Router) {
+			r.Use(requireAuthOrAPIKey)
+			r.Use(auditImpersonation)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireScope(entities.ScopeProfilesRead))
+
+				r.Get("/profile/me", profileHandler.GetMyProfile)
+				r.Get("/profile/me/photos", photoHandler.ListPhotos)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireScope(entities.ScopeProfilesWrite))
+
+				if rt.options.RequireVerifiedEmail {
+					r.With(requireVerifiedEmail).Post("/profile", profileHandler.CreateProfile)
+				} else {
+					r.Post("/profile", profileHandler.CreateProfile)
+				}
+				r.Put("/profile/me", profileHandler.UpdateProfile)
+				r.Patch("/profile/me", profileHandler.PatchProfile)
+				r.Patch("/profile/me/privacy", profileHandler.UpdatePrivacy)
+
+				r.Post("/profile/me/photos", photoHandler.UploadPhoto)
+				r.Put("/profile/me/photos/order", photoHandler.ReorderPhotos)
+				r.Post("/profile/me/photos/{id}/primary", photoHandler.SetPrimaryPhoto)
+				r.Delete("/profile/me/photos/{id}", photoHandler.DeletePhoto)
+			})
+		})
 
-		// Защищенные роуты (с авторизацией)
+		// Защищенные роуты (только с JWT токеном)
 		r.Group(func(r chi.Router) {
-			r.Use(authMiddleware.JWTAuthMiddleware(rt.authService))
+			r.Use(requireAuth)
+			r.Use(auditImpersonation)
+
+			r.Post("/email/verify/resend", emailVerificationHandler.ResendVerification)
+			r.Get("/account/sessions", sessionHandler.ListSessions)
+			r.Get("/account/api-keys", apiKeyHandler.ListAPIKeys)
+			r.Post("/logout", authHandler.Logout)
+
+			// Чувствительные действия недоступны администратору, вошедшему под пользователем
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.DenyImpersonation)
+
+				r.Delete("/account", accountHandler.DeleteAccount)
+				r.Get("/account/export", accountHandler.ExportData)
+				r.Put("/account/password", accountHandler.ChangePassword)
+				r.Put("/account/email", accountHandler.ChangeEmail)
+				r.Delete("/account/sessions/{id}", sessionHandler.RevokeSession)
+
+				r.Post("/account/2fa/enroll", twoFactorHandler.Enroll)
+				r.Post("/account/2fa/confirm", twoFactorHandler.Confirm)
+				r.Post("/account/2fa/disable", twoFactorHandler.Disable)
+
+				r.Post("/account/api-keys", apiKeyHandler.CreateAPIKey)
+				r.Delete("/account/api-keys/{id}", apiKeyHandler.RevokeAPIKey)
+
+				r.Post("/logout-all", authHandler.LogoutAll)
+			})
+		})
 
-			r.Get("/profile/me", profileHandler.GetMyProfile)
-			r.Post("/profile", profileHandler.CreateProfile)
-			r.Put("/profile/me", profileHandler.UpdateProfile)
+		// Администрирование: управление пользователями и модерация анкет
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(requireAuth)
+			r.Use(auditImpersonation)
+			r.Use(authMiddleware.DenyImpersonation)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireRole(entities.RoleAdmin))
+
+				r.Get("/users", adminHandler.ListUsers)
+				r.Post("/users/{id}/suspend", adminHandler.SuspendUser)
+				r.Post("/users/{id}/unsuspend", adminHandler.UnsuspendUser)
+				r.Post("/users/{id}/impersonate", adminHandler.Impersonate)
+				r.Get("/audit-log", adminHandler.ListAuditLog)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireRole(entities.RoleAdmin, entities.RoleModerator))
+
+				r.Put("/profiles/{id}", adminHandler.UpdateProfile)
+				r.Post("/profiles/{id}/hide", adminHandler.HideProfile)
+				r.Post("/profiles/{id}/unhide", adminHandler.UnhideProfile)
+			})
 		})
 	})
 
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Неудачные попытки входа по ключу (аккаунт или IP адрес).
+-- Счетчик начинается заново, если с последней неудачной попытки прошло больше окна наблюдения.
+CREATE TABLE login_attempts (
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Роли пользователя попадают в access токен; пустой список означает обычного пользователя
+ALTER TABLE users ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{}';
+
+-- Заблокированный пользователь не может войти и обновить токены
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMPTZ;
+
+-- Скрытая модератором анкета не видна в поиске и по ссылке
+ALTER TABLE profiles ADD COLUMN hidden_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE profiles DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE users DROP COLUMN IF EXISTS roles;
@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- Журнал действий администраторов от имени пользователей.
+-- Внешних ключей нет намеренно: записи должны сохраняться после удаления аккаунтов.
+CREATE TABLE audit_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    actor_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT '',
+    path TEXT NOT NULL DEFAULT '',
+    status INTEGER NOT NULL DEFAULT 0,
+    ip TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id, created_at DESC);
+CREATE INDEX idx_audit_log_user_id ON audit_log(user_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_audit_log_user_id;
+DROP INDEX IF EXISTS idx_audit_log_actor_id;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Версия анкеты для оптимистичной блокировки: увеличивается при каждом изменении
+-- и используется как ETag в ответах API
+ALTER TABLE profiles ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE profiles DROP COLUMN IF EXISTS version;
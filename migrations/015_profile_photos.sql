@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- Фотографии анкет. Файлы хранятся в хранилище по ключу storage_key:
+-- исходное изображение и уменьшенные копии лежат рядом под именами вариантов.
+-- При удалении анкеты фотографии отвязываются (profile_id = NULL),
+-- а фоновая задача удаляет их файлы и записи.
+CREATE TABLE profile_photos (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    profile_id INTEGER REFERENCES profiles(id) ON DELETE SET NULL,
+    storage_key TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_profile_photos_profile_id ON profile_photos(profile_id, position);
+CREATE INDEX idx_profile_photos_orphaned ON profile_photos(id) WHERE profile_id IS NULL;
+
+-- У анкеты может быть только одна основная фотография
+CREATE UNIQUE INDEX idx_profile_photos_primary ON profile_photos(profile_id) WHERE is_primary;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_profile_photos_primary;
+DROP INDEX IF EXISTS idx_profile_photos_orphaned;
+DROP INDEX IF EXISTS idx_profile_photos_profile_id;
+DROP TABLE IF EXISTS profile_photos;
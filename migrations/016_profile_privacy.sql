@@ -0,0 +1,40 @@
+-- +goose Up
+
+-- Видимость анкеты и ее полей: public - всем, registered - авторизованным пользователям,
+-- friends - друзьям владельца, private - только владельцу
+CREATE DOMAIN profile_visibility AS TEXT
+    CHECK (VALUE IN ('public', 'registered', 'friends', 'private'));
+
+-- По умолчанию все видно всем, как и до появления настроек
+ALTER TABLE profiles
+    ADD COLUMN profile_visibility profile_visibility NOT NULL DEFAULT 'public',
+    ADD COLUMN age_visibility profile_visibility NOT NULL DEFAULT 'public',
+    ADD COLUMN gender_visibility profile_visibility NOT NULL DEFAULT 'public',
+    ADD COLUMN city_visibility profile_visibility NOT NULL DEFAULT 'public',
+    ADD COLUMN interests_visibility profile_visibility NOT NULL DEFAULT 'public',
+    ADD COLUMN photos_visibility profile_visibility NOT NULL DEFAULT 'public';
+
+-- Проверяет, видно ли поле анкеты пользователя owner_id зрителю viewer_id (0 - аноним).
+-- friend_ids - друзья зрителя. Используется в поиске, чтобы по скрытым полям нельзя было фильтровать
+-- +goose StatementBegin
+CREATE FUNCTION profile_field_visible(visibility TEXT, owner_id INTEGER, viewer_id INTEGER, friend_ids INTEGER[])
+RETURNS BOOLEAN
+LANGUAGE sql IMMUTABLE
+AS $$
+    SELECT visibility = 'public'
+        OR owner_id = viewer_id
+        OR (visibility = 'registered' AND viewer_id <> 0)
+        OR (visibility = 'friends' AND owner_id = ANY(friend_ids))
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS profile_field_visible(TEXT, INTEGER, INTEGER, INTEGER[]);
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS photos_visibility,
+    DROP COLUMN IF EXISTS interests_visibility,
+    DROP COLUMN IF EXISTS city_visibility,
+    DROP COLUMN IF EXISTS gender_visibility,
+    DROP COLUMN IF EXISTS age_visibility,
+    DROP COLUMN IF EXISTS profile_visibility;
+DROP DOMAIN IF EXISTS profile_visibility;
@@ -0,0 +1,32 @@
+-- +goose Up
+
+-- Вместо возраста, который со временем устаревает, храним дату рождения
+ALTER TABLE profiles ADD COLUMN birth_date DATE;
+
+-- Точная дата рождения неизвестна: считаем, что с последнего дня рождения прошло полгода.
+-- Так вычисленный возраст совпадает с сохраненным и ошибается не больше чем на полгода
+UPDATE profiles
+SET birth_date = (CURRENT_DATE - make_interval(years => age, months => 6))::date
+WHERE age IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_profiles_age;
+ALTER TABLE profiles DROP COLUMN age;
+CREATE INDEX idx_profiles_birth_date ON profiles(birth_date);
+
+-- День рождения и знак зодиака по умолчанию видны только владельцу
+ALTER TABLE profiles
+    ADD COLUMN birthday_visibility profile_visibility NOT NULL DEFAULT 'private',
+    ADD COLUMN zodiac_visibility profile_visibility NOT NULL DEFAULT 'private';
+
+-- +goose Down
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS zodiac_visibility,
+    DROP COLUMN IF EXISTS birthday_visibility;
+
+DROP INDEX IF EXISTS idx_profiles_birth_date;
+ALTER TABLE profiles ADD COLUMN age INTEGER;
+UPDATE profiles
+SET age = date_part('year', age(birth_date))::int
+WHERE birth_date IS NOT NULL;
+ALTER TABLE profiles DROP COLUMN birth_date;
+CREATE INDEX idx_profiles_age ON profiles(age);
@@ -0,0 +1,172 @@
+-- +goose Up
+
+-- Приводит название интереса к виду, в котором оно хранится в interest_synonyms:
+-- нижний регистр, одиночные пробелы, ё заменяется на е. Должна совпадать с entities.NormalizeInterest
+-- +goose StatementBegin
+CREATE FUNCTION normalize_interest(value TEXT)
+RETURNS TEXT
+LANGUAGE sql IMMUTABLE
+AS $$
+    SELECT translate(lower(regexp_replace(btrim(value), '\s+', ' ', 'g')), 'ё', 'е')
+$$;
+-- +goose StatementEnd
+
+-- Канонические интересы. slug используется в API и в анкетах
+CREATE TABLE interests (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    slug TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Названия интересов на разных языках
+CREATE TABLE interest_labels (
+    interest_id INTEGER NOT NULL REFERENCES interests(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    label TEXT NOT NULL,
+    PRIMARY KEY (interest_id, locale)
+);
+
+-- Все написания интереса (slug, названия и синонимы) в нормализованном виде.
+-- По ним пользовательский ввод сопоставляется с каноническим интересом и работает автодополнение
+CREATE TABLE interest_synonyms (
+    synonym TEXT PRIMARY KEY,
+    interest_id INTEGER NOT NULL REFERENCES interests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_interest_synonyms_prefix ON interest_synonyms(synonym text_pattern_ops);
+CREATE INDEX idx_interest_synonyms_interest_id ON interest_synonyms(interest_id);
+
+-- Интересы анкет в порядке, заданном пользователем
+CREATE TABLE profile_interests (
+    profile_id INTEGER NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    interest_id INTEGER NOT NULL REFERENCES interests(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (profile_id, interest_id)
+);
+
+CREATE INDEX idx_profile_interests_interest_id ON profile_interests(interest_id);
+
+-- Начальная таксономия
+INSERT INTO interests (slug)
+VALUES ('music'), ('programming'), ('sports'), ('travel'), ('reading'), ('movies'), ('photography'),
+    ('cooking'), ('video-games'), ('board-games'), ('art'), ('dancing'), ('fitness'), ('yoga'), ('running'),
+    ('hiking'), ('football'), ('chess'), ('science'), ('technology'), ('languages'), ('theatre'),
+    ('fashion'), ('animals'), ('nature'), ('volunteering');
+
+INSERT INTO interest_labels (interest_id, locale, label)
+SELECT interests.id, labels.locale, labels.label
+FROM (VALUES
+    ('music', 'en', 'Music'), ('music', 'ru', 'Музыка'),
+    ('programming', 'en', 'Programming'), ('programming', 'ru', 'Программирование'),
+    ('sports', 'en', 'Sports'), ('sports', 'ru', 'Спорт'),
+    ('travel', 'en', 'Travel'), ('travel', 'ru', 'Путешествия'),
+    ('reading', 'en', 'Reading'), ('reading', 'ru', 'Чтение'),
+    ('movies', 'en', 'Movies'), ('movies', 'ru', 'Кино'),
+    ('photography', 'en', 'Photography'), ('photography', 'ru', 'Фотография'),
+    ('cooking', 'en', 'Cooking'), ('cooking', 'ru', 'Кулинария'),
+    ('video-games', 'en', 'Video games'), ('video-games', 'ru', 'Видеоигры'),
+    ('board-games', 'en', 'Board games'), ('board-games', 'ru', 'Настольные игры'),
+    ('art', 'en', 'Art'), ('art', 'ru', 'Искусство'),
+    ('dancing', 'en', 'Dancing'), ('dancing', 'ru', 'Танцы'),
+    ('fitness', 'en', 'Fitness'), ('fitness', 'ru', 'Фитнес'),
+    ('yoga', 'en', 'Yoga'), ('yoga', 'ru', 'Йога'),
+    ('running', 'en', 'Running'), ('running', 'ru', 'Бег'),
+    ('hiking', 'en', 'Hiking'), ('hiking', 'ru', 'Походы'),
+    ('football', 'en', 'Football'), ('football', 'ru', 'Футбол'),
+    ('chess', 'en', 'Chess'), ('chess', 'ru', 'Шахматы'),
+    ('science', 'en', 'Science'), ('science', 'ru', 'Наука'),
+    ('technology', 'en', 'Technology'), ('technology', 'ru', 'Технологии'),
+    ('languages', 'en', 'Languages'), ('languages', 'ru', 'Иностранные языки'),
+    ('theatre', 'en', 'Theatre'), ('theatre', 'ru', 'Театр'),
+    ('fashion', 'en', 'Fashion'), ('fashion', 'ru', 'Мода'),
+    ('animals', 'en', 'Animals'), ('animals', 'ru', 'Животные'),
+    ('nature', 'en', 'Nature'), ('nature', 'ru', 'Природа'),
+    ('volunteering', 'en', 'Volunteering'), ('volunteering', 'ru', 'Волонтерство')
+) AS labels(slug, locale, label)
+JOIN interests ON interests.slug = labels.slug;
+
+INSERT INTO interest_synonyms (synonym, interest_id)
+SELECT normalize_interest(synonyms.synonym), interests.id
+FROM (VALUES
+    ('music', 'музыку'), ('programming', 'coding'), ('programming', 'кодинг'),
+    ('programming', 'программист'), ('sports', 'sport'), ('travel', 'traveling'),
+    ('travel', 'travelling'), ('travel', 'путешествие'), ('reading', 'books'), ('reading', 'книги'),
+    ('movies', 'films'), ('movies', 'cinema'), ('movies', 'фильмы'), ('photography', 'photo'),
+    ('photography', 'фото'), ('cooking', 'готовка'), ('video-games', 'gaming'), ('video-games', 'games'),
+    ('video-games', 'игры'), ('video-games', 'компьютерные игры'), ('art', 'arts'), ('art', 'живопись'),
+    ('dancing', 'dance'), ('fitness', 'gym'), ('fitness', 'спортзал'), ('fitness', 'тренажерный зал'),
+    ('hiking', 'туризм'), ('football', 'soccer'), ('technology', 'tech'), ('technology', 'it'),
+    ('languages', 'языки'), ('theatre', 'theater'), ('animals', 'pets'), ('animals', 'питомцы')
+) AS synonyms(slug, synonym)
+JOIN interests ON interests.slug = synonyms.slug;
+
+INSERT INTO interest_synonyms (synonym, interest_id)
+SELECT normalize_interest(label), interest_id FROM interest_labels
+ON CONFLICT (synonym) DO NOTHING;
+
+INSERT INTO interest_synonyms (synonym, interest_id)
+SELECT slug, id FROM interests
+ON CONFLICT (synonym) DO NOTHING;
+
+-- Интересы из анкет, которых нет в таксономии, становятся новыми каноническими интересами
+-- со slug из нормализованного названия и исходным написанием в качестве названия
+INSERT INTO interests (slug)
+SELECT DISTINCT replace(normalize_interest(value), ' ', '-')
+FROM profiles CROSS JOIN LATERAL unnest(profiles.interests) AS value
+WHERE normalize_interest(value) <> '' AND NOT EXISTS (
+        SELECT 1 FROM interest_synonyms
+        WHERE interest_synonyms.synonym = normalize_interest(value)
+    )
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO interest_labels (interest_id, locale, label)
+SELECT DISTINCT ON (interests.id) interests.id,
+    CASE WHEN value ~ '[А-Яа-яЁё]' THEN 'ru' ELSE 'en' END,
+    btrim(value)
+FROM profiles CROSS JOIN LATERAL unnest(profiles.interests) AS value
+JOIN interests ON interests.slug = replace(normalize_interest(value), ' ', '-')
+WHERE NOT EXISTS (
+        SELECT 1 FROM interest_labels
+        WHERE interest_labels.interest_id = interests.id
+    )
+ORDER BY interests.id, value
+ON CONFLICT DO NOTHING;
+
+INSERT INTO interest_synonyms (synonym, interest_id)
+SELECT DISTINCT normalize_interest(value), interests.id
+FROM profiles CROSS JOIN LATERAL unnest(profiles.interests) AS value
+JOIN interests ON interests.slug = replace(normalize_interest(value), ' ', '-')
+ON CONFLICT (synonym) DO NOTHING;
+
+INSERT INTO interest_synonyms (synonym, interest_id)
+SELECT slug, id FROM interests
+ON CONFLICT (synonym) DO NOTHING;
+
+-- Переносим интересы анкет в связующую таблицу, сохраняя порядок
+INSERT INTO profile_interests (profile_id, interest_id, position)
+SELECT profiles.id, interest_synonyms.interest_id, MIN(items.position)
+FROM profiles
+CROSS JOIN LATERAL unnest(profiles.interests) WITH ORDINALITY AS items(value, position)
+JOIN interest_synonyms ON interest_synonyms.synonym = normalize_interest(items.value)
+GROUP BY profiles.id, interest_synonyms.interest_id;
+
+DROP INDEX IF EXISTS idx_profiles_interests;
+ALTER TABLE profiles DROP COLUMN interests;
+
+-- +goose Down
+ALTER TABLE profiles ADD COLUMN interests TEXT[];
+UPDATE profiles
+SET interests = ARRAY(
+        SELECT interests.slug
+        FROM profile_interests
+        JOIN interests ON interests.id = profile_interests.interest_id
+        WHERE profile_interests.profile_id = profiles.id
+        ORDER BY profile_interests.position
+    );
+CREATE INDEX idx_profiles_interests ON profiles USING GIN(interests);
+
+DROP TABLE IF EXISTS profile_interests;
+DROP TABLE IF EXISTS interest_synonyms;
+DROP TABLE IF EXISTS interest_labels;
+DROP TABLE IF EXISTS interests;
+DROP FUNCTION IF EXISTS normalize_interest(TEXT);
@@ -0,0 +1,12 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+
+-- Поиск по началу имени и фамилии без учета регистра: lower(...) LIKE 'префикс%'.
+-- text_pattern_ops позволяет использовать индекс для LIKE при любой сортировке базы.
+-- Индексы строятся без блокировки записи в profiles, поэтому миграция идет вне транзакции
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_profiles_first_name_prefix ON profiles (lower(first_name) text_pattern_ops);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_profiles_last_name_prefix ON profiles (lower(last_name) text_pattern_ops);
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_profiles_last_name_prefix;
+DROP INDEX CONCURRENTLY IF EXISTS idx_profiles_first_name_prefix;
@@ -0,0 +1,171 @@
+-- +goose Up
+
+-- Похожесть строк по триграммам для поиска с опечатками
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Поисковые документы анкет. Хранятся отдельно, чтобы не читать их вместе с анкетой.
+-- document: имя и фамилия (вес A), город (B), slug и названия интересов (C).
+-- names и city - имя с фамилией и город в нижнем регистре для поиска по похожести
+CREATE TABLE profile_search_documents (
+    profile_id INTEGER PRIMARY KEY REFERENCES profiles(id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL,
+    names TEXT NOT NULL,
+    city TEXT NOT NULL
+);
+
+CREATE INDEX idx_profile_search_documents_document ON profile_search_documents USING GIN(document);
+CREATE INDEX idx_profile_search_documents_names ON profile_search_documents USING GIN(names gin_trgm_ops);
+CREATE INDEX idx_profile_search_documents_city ON profile_search_documents USING GIN(city gin_trgm_ops);
+
+-- Пересчитывает поисковые документы анкет. Словарь simple не зависит от языка,
+-- поэтому одинаково разбирает русские и английские слова
+-- +goose StatementBegin
+CREATE FUNCTION refresh_profile_search_documents(profile_ids INTEGER[])
+RETURNS VOID
+LANGUAGE sql
+AS $$
+    INSERT INTO profile_search_documents (profile_id, document, names, city)
+    SELECT profiles.id,
+        setweight(to_tsvector('simple', profiles.first_name || ' ' || profiles.last_name), 'A') ||
+            setweight(to_tsvector('simple', coalesce(profiles.city, '')), 'B') ||
+            setweight(to_tsvector('simple', coalesce(profile_interests_text.value, '')), 'C'),
+        lower(profiles.first_name || ' ' || profiles.last_name),
+        lower(coalesce(profiles.city, ''))
+    FROM profiles
+    LEFT JOIN LATERAL (
+        SELECT string_agg(replace(interests.slug, '-', ' ') || ' ' || coalesce(labels.value, ''), ' ') AS value
+        FROM profile_interests
+        JOIN interests ON interests.id = profile_interests.interest_id
+        LEFT JOIN LATERAL (
+            SELECT string_agg(interest_labels.label, ' ') AS value
+            FROM interest_labels
+            WHERE interest_labels.interest_id = interests.id
+        ) AS labels ON true
+        WHERE profile_interests.profile_id = profiles.id
+    ) AS profile_interests_text ON true
+    WHERE profiles.id = ANY(profile_ids)
+    ON CONFLICT (profile_id) DO UPDATE
+    SET document = EXCLUDED.document, names = EXCLUDED.names, city = EXCLUDED.city
+$$;
+-- +goose StatementEnd
+
+-- Документ обновляется при изменении имени, фамилии, города и интересов анкеты.
+-- Названия существующих интересов не меняются, поэтому их изменения не отслеживаются
+-- +goose StatementBegin
+CREATE FUNCTION profiles_refresh_search_document()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    PERFORM refresh_profile_search_documents(ARRAY[NEW.id]);
+    RETURN NULL;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION profile_interests_refresh_search_documents()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    PERFORM refresh_profile_search_documents(ARRAY(SELECT DISTINCT profile_id FROM changed));
+    RETURN NULL;
+END
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER profiles_search_document
+    AFTER INSERT OR UPDATE OF first_name, last_name, city ON profiles
+    FOR EACH ROW EXECUTE FUNCTION profiles_refresh_search_document();
+
+CREATE TRIGGER profile_interests_search_document_insert
+    AFTER INSERT ON profile_interests
+    REFERENCING NEW TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION profile_interests_refresh_search_documents();
+
+CREATE TRIGGER profile_interests_search_document_delete
+    AFTER DELETE ON profile_interests
+    REFERENCING OLD TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION profile_interests_refresh_search_documents();
+
+SELECT refresh_profile_search_documents(ARRAY(SELECT id FROM profiles));
+
+-- Превращает текст запроса в запрос полнотекстового поиска: все слова, каждое как начало слова.
+-- Слова выделяются тем же разбором, что и в документах, поэтому спецсимволы tsquery не мешают
+-- +goose StatementBegin
+CREATE FUNCTION profile_search_query(q TEXT)
+RETURNS TSQUERY
+LANGUAGE sql IMMUTABLE
+AS $$
+    SELECT string_agg('''' || replace(replace(words.lexeme, '\', '\\'), '''', '''''') || ''':*', ' & ')::tsquery
+    FROM unnest(to_tsvector('simple', q)) AS words
+$$;
+-- +goose StatementEnd
+
+-- Релевантность анкеты запросу q по полям, видимым зрителю viewer_id: ранг полнотекстового
+-- совпадения плюс похожесть имени и города, если она выше порога pg_trgm.similarity_threshold.
+-- 0 - анкета запросу не соответствует
+-- +goose StatementBegin
+CREATE FUNCTION profile_search_score(
+    profile_id INTEGER,
+    owner_id INTEGER,
+    city_visibility TEXT,
+    interests_visibility TEXT,
+    viewer_id INTEGER,
+    friend_ids INTEGER[],
+    q TEXT
+)
+RETURNS REAL
+LANGUAGE sql STABLE
+AS $$
+    SELECT coalesce((
+        SELECT (
+            CASE WHEN visible.document @@ visible.query THEN ts_rank(visible.document, visible.query) ELSE 0 END +
+            CASE WHEN search.names % visible.term THEN similarity(search.names, visible.term) ELSE 0 END +
+            CASE WHEN visible.city AND search.city % visible.term THEN similarity(search.city, visible.term) ELSE 0 END
+        )::real
+        FROM profile_search_documents AS search
+        CROSS JOIN LATERAL (
+            SELECT ts_filter(search.document, array_remove(ARRAY[
+                    'a',
+                    CASE WHEN profile_field_visible(city_visibility, owner_id, viewer_id, friend_ids) THEN 'b' END,
+                    CASE WHEN profile_field_visible(interests_visibility, owner_id, viewer_id, friend_ids) THEN 'c' END
+                ]::"char"[], NULL)) AS document,
+                profile_field_visible(city_visibility, owner_id, viewer_id, friend_ids) AS city,
+                profile_search_query(q) AS query,
+                lower(q) AS term
+        ) AS visible
+        WHERE search.profile_id = profile_search_score.profile_id
+    ), 0)
+$$;
+-- +goose StatementEnd
+
+-- Выделяет в value совпадения с запросом q символами U+0001 и U+0002. Если слова запроса
+-- не найдены, но value похоже на запрос, выделяется целиком. NULL - совпадений нет
+-- +goose StatementBegin
+CREATE FUNCTION profile_search_highlight(value TEXT, q TEXT)
+RETURNS TEXT
+LANGUAGE sql STABLE
+AS $$
+    SELECT CASE
+        WHEN to_tsvector('simple', value) @@ profile_search_query(q) THEN
+            ts_headline('simple', value, profile_search_query(q),
+                'StartSel=' || chr(1) || ', StopSel=' || chr(2) || ', HighlightAll=true')
+        WHEN lower(value) % lower(q) THEN chr(1) || value || chr(2)
+    END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS profile_search_highlight(TEXT, TEXT);
+DROP FUNCTION IF EXISTS profile_search_score(INTEGER, INTEGER, TEXT, TEXT, INTEGER, INTEGER[], TEXT);
+DROP FUNCTION IF EXISTS profile_search_query(TEXT);
+DROP TRIGGER IF EXISTS profile_interests_search_document_delete ON profile_interests;
+DROP TRIGGER IF EXISTS profile_interests_search_document_insert ON profile_interests;
+DROP TRIGGER IF EXISTS profiles_search_document ON profiles;
+DROP FUNCTION IF EXISTS profile_interests_refresh_search_documents();
+DROP FUNCTION IF EXISTS profiles_refresh_search_document();
+DROP FUNCTION IF EXISTS refresh_profile_search_documents(INTEGER[]);
+DROP TABLE IF EXISTS profile_search_documents;
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,12 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+
+-- Порядок поиска newest и name с продолжением по курсору: (created_at, id) < (...) и
+-- (lower(first_name), lower(last_name), id) > (...) читают индекс с позиции курсора,
+-- поэтому дальние страницы не медленнее первых
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_profiles_created_at_id ON profiles (created_at, id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_profiles_name_sort ON profiles (lower(first_name), lower(last_name), id);
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_profiles_name_sort;
+DROP INDEX CONCURRENTLY IF EXISTS idx_profiles_created_at_id;
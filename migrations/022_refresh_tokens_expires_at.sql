@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Истекшие refresh токены периодически удаляются по сроку действия
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;